@@ -0,0 +1,51 @@
+package genv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvarArgs(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: `--flag "quoted value" -x`}
+		assert.Equal(t, []string{"--flag", "quoted value", "-x"}, ev.Args())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: `--flag "unterminated`}
+		assert.Panics(t, func() { ev.Args() })
+	})
+}
+
+func TestEvarTryArgs(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected []string
+		err      error
+	}{
+		"simple":           {"-a -b  c", false, []string{"-a", "-b", "c"}, nil},
+		"double quoted":    {`--flag "quoted value" -x`, false, []string{"--flag", "quoted value", "-x"}, nil},
+		"single quoted":    {`'it''s' '"raw" \n'`, false, []string{"its", `"raw" \n`}, nil},
+		"escapes":          {`a\ b "c\"d" "e\f"`, false, []string{"a b", `c"d`, `e\f`}, nil},
+		"empty quotes":     {`"" x`, false, []string{"", "x"}, nil},
+		"unterminated":     {`--flag "quoted value`, false, nil, errUnterminatedQuote},
+		"dangling escape":  {`--flag \`, false, nil, errDanglingEscape},
+		"empty":            {"", false, nil, ErrRequiredEnvironmentVariable},
+		"optional":         {"", true, nil, nil},
+		"only whitespaces": {"   ", false, nil, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			actual, err := ev.TryArgs()
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.ErrorContains(t, err, "TEST_VAR")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package genv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// parseByteSize parses a whole number of bytes followed by an optional
+// decimal (KB, MB, GB, TB) or binary (KiB, MiB, GiB, TiB) unit, such as
+// "256MB" or "1 GiB". Units are matched without regard to case.
+func parseByteSize(value string) (int64, error) {
+	i := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+	if i < 0 {
+		i = len(value)
+	}
+	number, unit := value[:i], strings.TrimSpace(value[i:])
+	if number == "" {
+		return 0, fmt.Errorf("%q does not start with a number of bytes", value)
+	}
+
+	multiplier, ok := byteSizeUnits[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q in byte size %q; "+
+			"use B, KB, MB, GB, TB, KiB, MiB, GiB, or TiB", unit, value)
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("byte size %q overflows int64", value)
+	}
+	return n * multiplier, nil
+}
+
+// Returns the value of the environment variable as a number of bytes,
+// written with an optional unit such as "256MB" or "1GiB". Panics if the
+// value or its unit is invalid.
+func (ev *Var) ByteSize() int64 {
+	return mustParse(ev, (*Var).TryByteSize)
+}
+
+// Returns the value of the environment variable as a number of bytes,
+// written with an optional unit such as "256MB" or "1GiB". KB, MB, GB, and
+// TB are powers of 1000, while KiB, MiB, GiB, and TiB are powers of 1024.
+func (ev *Var) TryByteSize() (int64, error) {
+	return parse(ev, parseByteSize)
+}
@@ -0,0 +1,40 @@
+package genv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvarTryByteSize(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected int64
+		err      string
+	}{
+		"bare":     {"512", 512, ""},
+		"bytes":    {"512B", 512, ""},
+		"decimal":  {"256MB", 256_000_000, ""},
+		"binary":   {"1GiB", 1 << 30, ""},
+		"spaced":   {"4 KiB", 4096, ""},
+		"case":     {"2tb", 2_000_000_000_000, ""},
+		"unknown":  {"10XB", 0, `BUFFER_SIZE is invalid: unknown unit "XB" in byte size "10XB"; use B, KB, MB, GB, TB, KiB, MiB, GiB, or TiB`},
+		"noNumber": {"MB", 0, `BUFFER_SIZE is invalid: "MB" does not start with a number of bytes`},
+		"negative": {"-1MB", 0, `BUFFER_SIZE is invalid: "-1MB" does not start with a number of bytes`},
+		"fraction": {"1.5GB", 0, `BUFFER_SIZE is invalid: unknown unit ".5GB" in byte size "1.5GB"; use B, KB, MB, GB, TB, KiB, MiB, GiB, or TiB`},
+		"overflow": {"9000000TiB", 0, `BUFFER_SIZE is invalid: byte size "9000000TiB" overflows int64`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "BUFFER_SIZE", value: test.value}
+			actual, err := ev.TryByteSize()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				assert.Panics(t, func() { ev.ByteSize() })
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package genv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Loads the JSON object in the file at path as a layer of values that is
+// consulted when a variable is absent from the environment, taking priority
+// over any value passed to Default. Nested objects are flattened by joining
+// their keys with "_", so {"DB": {"HOST": "x"}} provides DB_HOST. A missing
+// file is ignored; use WithRequiredConfigFile to treat it as an error.
+// Panics if the file cannot be read or parsed.
+func WithConfigFile(path string) genvOpt {
+	return withConfigFile(path, false)
+}
+
+// Like WithConfigFile, but panics if the file does not exist.
+func WithRequiredConfigFile(path string) genvOpt {
+	return withConfigFile(path, true)
+}
+
+func withConfigFile(path string, required bool) genvOpt {
+	return func(genv *Genv) {
+		values, err := loadConfigFile(path)
+		if errors.Is(err, fs.ErrNotExist) && !required {
+			return
+		}
+		if err != nil {
+			panic(err)
+		}
+		if genv.fileValues == nil {
+			genv.fileValues = make(map[string]string, len(values))
+		}
+		for key, val := range values {
+			genv.fileValues[key] = val
+		}
+	}
+}
+
+func loadConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	var obj map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(obj))
+	if err := flattenConfig("", obj, values); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flattenConfig renders every scalar in obj as the string an environment
+// variable would hold. Arrays are kept in their JSON form.
+func flattenConfig(prefix string, obj map[string]any, out map[string]string) error {
+	for key, val := range obj {
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch val := val.(type) {
+		case nil:
+			continue
+		case string:
+			out[key] = val
+		case json.Number:
+			out[key] = val.String()
+		case bool:
+			out[key] = fmt.Sprint(val)
+		case map[string]any:
+			if err := flattenConfig(key, val, out); err != nil {
+				return err
+			}
+		default:
+			raw, err := json.Marshal(val)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			out[key] = string(raw)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package genv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithConfigFile(t *testing.T) {
+	path := writeConfigFile(t, `{
+		"FILE_VAR": "file",
+		"OVERRIDDEN_VAR": "file",
+		"PORT": 8080,
+		"DEBUG": true,
+		"HOSTS": ["a", "b"],
+		"UNSET": null,
+		"DB": {"HOST": "db.local", "POOL": {"SIZE": 4}}
+	}`)
+
+	t.Setenv("OVERRIDDEN_VAR", "env")
+	genv := New(WithConfigFile(path), WithAllowDefault(func(*Genv) bool { return true }))
+
+	assert.Equal(t, "env", genv.Var("OVERRIDDEN_VAR").Default("default").String())
+	assert.Equal(t, "file", genv.Var("FILE_VAR").Default("default").String())
+	assert.Equal(t, "default", genv.Var("MISSING_VAR").Default("default").String())
+	assert.Equal(t, 8080, genv.Var("PORT").Int())
+	assert.True(t, genv.Var("DEBUG").Bool())
+	assert.Equal(t, `["a","b"]`, genv.Var("HOSTS").String())
+	assert.False(t, genv.Var("UNSET").found)
+	assert.Equal(t, "db.local", genv.Var("DB_HOST").String())
+	assert.Equal(t, 4, genv.Var("DB_POOL_SIZE").Int())
+}
+
+func TestPreferFile(t *testing.T) {
+	path := writeConfigFile(t, `{"LOCAL_VAR": "file", "SIBLING_VAR": "file"}`)
+	t.Setenv("LOCAL_VAR", "env")
+	t.Setenv("SIBLING_VAR", "env")
+	t.Setenv("ENV_ONLY_VAR", "env")
+
+	t.Run("WithFile", func(t *testing.T) {
+		genv := New(WithConfigFile(path))
+		assert.Equal(t, "file", genv.Var("LOCAL_VAR").PreferFile().String())
+		assert.Equal(t, "env", genv.Var("SIBLING_VAR").String())
+		assert.Equal(t, "env", genv.Var("ENV_ONLY_VAR").PreferFile().String())
+	})
+
+	t.Run("WithoutFile", func(t *testing.T) {
+		genv := New()
+		assert.Equal(t, "env", genv.Var("LOCAL_VAR").PreferFile().String())
+	})
+}
+
+func TestWithConfigFileMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+
+	t.Run("Optional", func(t *testing.T) {
+		assert.NotPanics(t, func() { New(WithConfigFile(path)) })
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		assert.Panics(t, func() { New(WithRequiredConfigFile(path)) })
+	})
+}
+
+func TestWithConfigFileInvalid(t *testing.T) {
+	path := writeConfigFile(t, `["not", "an", "object"]`)
+	assert.Panics(t, func() { New(WithConfigFile(path)) })
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
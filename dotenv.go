@@ -0,0 +1,119 @@
+package genv
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Loads the KEY=value lines of the .env file at path into the same layer
+// of values as WithConfigFile, without changing the process environment.
+// Lines may start with "export", values may be single or double quoted,
+// and blank lines and "#" comments are skipped. Values set in the
+// environment take precedence unless WithPreferFile is given. A missing
+// file is ignored. Panics if the file cannot be read or parsed.
+func WithDotEnv(path string) genvOpt {
+	return func(genv *Genv) {
+		values, err := loadDotEnv(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+		if err != nil {
+			panic(err)
+		}
+		if genv.fileValues == nil {
+			genv.fileValues = make(map[string]string, len(values))
+		}
+		for key, val := range values {
+			genv.fileValues[key] = val
+		}
+	}
+}
+
+// Gives values loaded by WithConfigFile or WithDotEnv precedence over the
+// environment for every variable, like PreferFile does for one.
+func WithPreferFile() genvOpt {
+	return func(genv *Genv) {
+		genv.preferFile = true
+	}
+}
+
+func loadDotEnv(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("dotenv file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("dotenv file %s: line %d: expected KEY=value", path, n)
+		}
+		value, err := parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("dotenv file %s: line %d: %w", path, n, err)
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("dotenv file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Unquotes a value. Double-quoted values support the escapes \n, \", and
+// \\, while single-quoted values are taken literally. A "#" starts a
+// comment after a quoted value, or after whitespace in an unquoted one.
+func parseDotEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	quote := value[0]
+	if quote != '"' && quote != '\'' {
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		return strings.TrimSpace(value), nil
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == quote:
+			rest := strings.TrimSpace(value[i+1:])
+			if rest != "" && !strings.HasPrefix(rest, "#") {
+				return "", fmt.Errorf("unexpected %q after quoted value", rest)
+			}
+			return b.String(), nil
+		case c == '\\' && quote == '"' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case '"', '\\':
+				b.WriteByte(value[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(value[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated quoted value")
+}
@@ -0,0 +1,68 @@
+package genv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDotEnv(t *testing.T) {
+	path := writeDotEnv(t, `
+# Local development settings
+export HOST=localhost
+PORT = 8080 # inline comment
+DOUBLE="two words # not a comment"
+ESCAPED="line\nbreak \"quoted\""
+SINGLE='raw\n value' # comment
+EMPTY=
+OVERRIDDEN=file
+`)
+	t.Setenv("OVERRIDDEN", "env")
+
+	t.Run("Values", func(t *testing.T) {
+		genv := New(WithDotEnv(path))
+		assert.Equal(t, "localhost", genv.Var("HOST").String())
+		assert.Equal(t, 8080, genv.Var("PORT").Int())
+		assert.Equal(t, "two words # not a comment", genv.Var("DOUBLE").String())
+		assert.Equal(t, "line\nbreak \"quoted\"", genv.Var("ESCAPED").String())
+		assert.Equal(t, `raw\n value`, genv.Var("SINGLE").String())
+		assert.True(t, genv.Var("EMPTY").found)
+		assert.Equal(t, "env", genv.Var("OVERRIDDEN").String())
+
+		_, found := os.LookupEnv("HOST")
+		assert.False(t, found)
+	})
+
+	t.Run("PreferFile", func(t *testing.T) {
+		genv := New(WithDotEnv(path), WithPreferFile())
+		assert.Equal(t, "file", genv.Var("OVERRIDDEN").String())
+		assert.Equal(t, "localhost", genv.Var("HOST").String())
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		genv := New(WithDotEnv(filepath.Join(t.TempDir(), ".env")))
+		assert.False(t, genv.Var("HOST").found)
+	})
+
+	for name, content := range map[string]string{
+		"noSeparator":  "HOST",
+		"emptyKey":     "=value",
+		"unterminated": `HOST="localhost`,
+		"trailing":     `HOST="localhost" extra`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := writeDotEnv(t, content)
+			assert.Panics(t, func() { New(WithDotEnv(path)) })
+		})
+	}
+}
+
+func writeDotEnv(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
@@ -0,0 +1,33 @@
+package genv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseClockDuration parses HH:MM:SS or MM:SS into a duration. The leading
+// component may exceed its usual range, so "90:00" is ninety minutes.
+func parseClockDuration(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("%q is not in HH:MM:SS or MM:SS form", value)
+	}
+
+	units := []time.Duration{time.Hour, time.Minute, time.Second}[3-len(parts):]
+	var result time.Duration
+	for i, part := range parts {
+		// Atoi alone would accept a sign, such as "+1".
+		n, err := strconv.Atoi(part)
+		if err != nil || strings.TrimLeft(part, "0123456789") != "" || (i > 0 && n >= 60) {
+			return 0, fmt.Errorf("invalid component %q in clock duration %q", part, value)
+		}
+		if time.Duration(n) > (math.MaxInt64-result)/units[i] {
+			return 0, fmt.Errorf("clock duration %q is out of range", value)
+		}
+		result += time.Duration(n) * units[i]
+	}
+	return result, nil
+}
@@ -1,30 +1,79 @@
 package genv
 
 import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
 	"net/url"
 	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type (
 	Genv struct {
 		allowDefault func(*Genv) bool
 		splitKey     string
+		logger       *slog.Logger
+		name         string
+		fileValues   map[string]string
+		preferFile   bool
+		stripPrefix  string
+		prefix       string
+		parent       *Genv
+		reservedKeys map[string]struct{}
+		clock        func() time.Time
+		source       Source
+		stdin        io.Reader
+		registry     *registry
+		boolTokens   map[string]bool
+		strict       bool
+
+		defaultSentinel string
+	}
+
+	// Records the variables declared on an instance and its groups, in
+	// declaration order, so that they can be reported on after parsing.
+	// Every declaration of a key is kept, so that declaring it again cannot
+	// undo an earlier Secret, and keys are read only when reporting, so that
+	// DeprecatedFor can still change them.
+	registry struct {
+		vars []*Var
 	}
 )
 
 func New(opts ...genvOpt) *Genv {
 	genv := &Genv{
 		allowDefault: func(genv *Genv) bool {
-			return genv.
-				Var("GENV_ALLOW_DEFAULT").
+			// A reserved or invalid GENV_ALLOW_DEFAULT forbids defaults
+			// rather than failing every variable that has one.
+			allow, err := genv.
+				root().
+				newVar("GENV_ALLOW_DEFAULT").
 				Default("false", genv.WithAllowDefaultAlways()).
-				Bool()
+				TryBool()
+			return err == nil && allow
 		},
 		splitKey: ",",
+		clock:    time.Now,
+		source:   EnvSource{},
+		registry: new(registry),
 	}
 
 	for _, opt := range opts {
@@ -45,19 +94,187 @@ func WithAllowDefault(allowFn func(*Genv) bool) genvOpt {
 	}
 }
 
+// Forbids defaults regardless of GENV_ALLOW_DEFAULT, so that a variable
+// left unset fails with ErrRequiredEnvironmentVariable even if Default was
+// called. Defaults given their own allow option, such as
+// WithAllowDefaultAlways, still apply. This suits CI checks of production
+// configuration. It takes precedence over WithAllowDefault, whichever order
+// they are given in.
+func WithStrictDefaults() genvOpt {
+	return func(genv *Genv) {
+		genv.strict = true
+	}
+}
+
+// Sets the logger used to report notable decisions, such as a value
+// being clamped. Nothing is logged unless a logger is provided.
+func WithLogger(logger *slog.Logger) genvOpt {
+	return func(genv *Genv) {
+		genv.logger = logger
+	}
+}
+
+// Names the instance so that its log records and errors can be told
+// apart from those of other instances in the same process. Log records
+// carry the name in a "genv" attribute and errors are prefixed with it.
+func WithName(name string) genvOpt {
+	return func(genv *Genv) {
+		genv.name = name
+	}
+}
+
+// Reserves keys that must never be read from the environment, such as
+// LD_PRELOAD. Declaring a variable with a reserved key makes parsing it fail
+// with ErrReservedKey, and the key is never looked up.
+func WithReservedKeys(keys ...string) genvOpt {
+	return func(genv *Genv) {
+		if genv.reservedKeys == nil {
+			genv.reservedKeys = make(map[string]struct{}, len(keys))
+		}
+		for _, key := range keys {
+			genv.reservedKeys[key] = struct{}{}
+		}
+	}
+}
+
+// Treats variables whose value is exactly sentinel, such as "default", as
+// if they were unset, so that their fallback applies (subject to the usual
+// allow-default rules). This helps templating systems that cannot omit a
+// variable entirely. Disabled unless a non-empty sentinel is given.
+func WithDefaultSentinel(sentinel string) genvOpt {
+	return func(genv *Genv) {
+		genv.defaultSentinel = sentinel
+	}
+}
+
+// Reads variables from a snapshot of the environment taken when the
+// instance is created, so that later changes to the process environment
+// do not affect it.
+func WithSnapshot() genvOpt {
+	return WithEnvSnapshotFunc(os.Environ)
+}
+
+// Reads variables from the snapshot returned by environ, a list of
+// "KEY=value" entries in the form returned by os.Environ, instead of the
+// process environment. This lets tests supply a fixed environment without
+// mutating the real one.
+func WithEnvSnapshotFunc(environ func() []string) genvOpt {
+	return func(genv *Genv) {
+		entries := environ()
+		snapshot := make(envSnapshot, len(entries))
+		for _, entry := range entries {
+			// Skip the first byte so that Windows entries such as
+			// "=C:=C:\" keep their leading "=" in the key.
+			if i := strings.Index(entry[min(1, len(entry)):], "=") + 1; i > 0 {
+				snapshot[entry[:i]] = entry[i+1:]
+			}
+		}
+		genv.source = snapshot
+	}
+}
+
+// Sets the clock used for time-dependent behavior, such as defaults
+// computed from Now. Defaults to time.Now; inject a fixed clock to make
+// such behavior deterministic in tests.
+func WithClock(clock func() time.Time) genvOpt {
+	return func(genv *Genv) {
+		genv.clock = clock
+	}
+}
+
+// Returns the current time according to the configured clock.
+func (genv *Genv) Now() time.Time {
+	return genv.clock()
+}
+
+// Reads the value of any variable set to "-" from standard input, trimmed of
+// surrounding whitespace, following the command-line convention for piping
+// secrets with TOKEN=-. Reading blocks until standard input is closed,
+// and since it is consumed entirely, only one variable can be read this way.
+func WithStdinSentinel() genvOpt {
+	return func(genv *Genv) {
+		genv.stdin = os.Stdin
+	}
+}
+
+// Reads the environment as if prefix were stripped from its keys, for
+// platforms that namespace every variable, so that MYAPP_PORT satisfies
+// Var("PORT") given the prefix "MYAPP_". A prefixed key takes precedence
+// over the same key without the prefix. This is the inverse of Group.
+func WithStripPrefix(prefix string) genvOpt {
+	return func(genv *Genv) {
+		genv.stripPrefix = prefix
+	}
+}
+
+// Accepts the given tokens, such as "oui" and "non", as booleans in
+// addition to those understood by strconv.ParseBool. Tokens are matched
+// without regard to case.
+func WithBoolTokens(truthy, falsy []string) genvOpt {
+	return func(genv *Genv) {
+		if genv.boolTokens == nil {
+			genv.boolTokens = make(map[string]bool, len(truthy)+len(falsy))
+		}
+		for _, token := range truthy {
+			genv.boolTokens[strings.ToLower(token)] = true
+		}
+		for _, token := range falsy {
+			genv.boolTokens[strings.ToLower(token)] = false
+		}
+	}
+}
+
+// Returns a child instance whose variables are namespaced under prefix, so
+// that Var("HOST") on env.Group("DB") reads DB_HOST. The child shares all
+// options of its parent, and groups may be nested.
+func (genv *Genv) Group(prefix string) *Genv {
+	child := *genv
+	child.prefix = genv.prefix + prefix + "_"
+	child.parent = genv
+	return &child
+}
+
+// Returns the top-level instance that any groups were derived from.
+func (genv *Genv) root() *Genv {
+	for genv.parent != nil {
+		genv = genv.parent
+	}
+	return genv
+}
+
 // Returns a new environment variable with the given key.
 func (genv *Genv) Var(key string, opts ...envVarOpt) *Var {
+	ev := genv.newVar(key)
+	if genv.stdin != nil && ev.found && ev.value == "-" {
+		data, err := io.ReadAll(genv.stdin)
+		if err != nil {
+			ev.err = fmt.Errorf("reading standard input: %w", err)
+		}
+		ev.value = strings.TrimSpace(string(data))
+	}
+
+	for _, opt := range opts {
+		opt(ev)
+	}
+
+	genv.registry.add(ev)
+	return ev
+}
+
+// Returns a new environment variable without recording it or reading
+// standard input for it, for variables that genv only inspects, such as
+// those it reads for its own configuration.
+func (genv *Genv) newVar(key string) *Var {
+	key = genv.prefix + key
 	ev := new(Var)
 	ev.key = key
 	ev.allowDefault = genv.allowDefault
 	ev.splitKey = genv.splitKey
-	ev.value, ev.found = os.LookupEnv(key)
+	ev.value, ev.origin, ev.found = genv.resolve(key)
 	ev.genv = genv
-
-	for _, opt := range opts {
-		opt(ev)
+	if genv.reserved(key) {
+		ev.err = ErrReservedKey
 	}
-
 	return ev
 }
 
@@ -78,9 +295,47 @@ type Var struct {
 	value        string
 	found        bool
 	optional     bool
+	mustBeSet    bool
+	skipped      bool
 	allowDefault func(*Genv) bool
 	splitKey     string
 	genv         *Genv
+
+	defaultScheme string
+	queryParams   []string
+	encoding      *base64.Encoding
+	maxElements   int
+	pairSep       string
+	defaultElems  []string
+	elemDefaults  []string
+	decimalComma  bool
+	sorted        bool
+	flexibleList  bool
+	saturating    bool
+	bpsBounded    bool
+	autoDelimiter bool
+	trimSpace     bool
+	boolLoose     bool
+	unique        bool
+	clockDuration bool
+	everyPhrase   bool
+	round         time.Duration
+	glob          string
+	charset       string
+	mustExist     bool
+	caseFold      bool
+	validators    []validator
+	secret        bool
+	hasDefault    bool
+	origin        Origin
+	keepLast      int
+	err           error
+}
+
+// A check added with Validate or OneOf, which applies to values of typ.
+type validator struct {
+	typ reflect.Type
+	fn  func(any) (any, error)
 }
 
 type fallback struct {
@@ -94,21 +349,173 @@ func (ev *Var) Optional() *Var {
 	return ev
 }
 
+// Matches the value against a set of choices, such as with OneOf or
+// ManyChoiceLenient, without regard to case. The result is the choice as
+// declared, so "Info" matching the choice "INFO" yields "INFO".
+func (ev *Var) CaseFold() *Var {
+	ev.caseFold = true
+	return ev
+}
+
+// Marks the value as sensitive so that it is masked wherever genv reports
+// it, such as in LogConfig.
+func (ev *Var) Secret() *Var {
+	ev.secret = true
+	return ev
+}
+
+// Marks the value as semi-sensitive, such as an account number, so that
+// all but its last n characters are masked wherever genv reports it. Values
+// of n characters or fewer are masked entirely.
+func (ev *Var) RedactKeeping(n int) *Var {
+	ev.secret = true
+	ev.keepLast = n
+	return ev
+}
+
+// Returns the value as it should be reported, masked if it is sensitive.
+func (ev *Var) display() string {
+	if !ev.secret {
+		return ev.value
+	}
+	runes := []rune(ev.value)
+	if ev.keepLast <= 0 || len(runes) <= ev.keepLast {
+		return "****"
+	}
+	return "****" + string(runes[len(runes)-ev.keepLast:])
+}
+
+// Marks the variable as deprecated in favor of newKey. The value of newKey is
+// used whenever it is set, and a conflict warning is logged if the deprecated
+// key is set as well. Otherwise the value of the deprecated key is used and a
+// migration warning is logged. If neither is set, the variable is reported
+// under newKey, and any default already given still applies.
+func (ev *Var) DeprecatedFor(newKey string) *Var {
+	newKey = ev.genv.prefix + newKey
+	if ev.genv.reserved(newKey) {
+		ev.key, ev.value, ev.found, ev.origin = newKey, "", false, OriginUnset
+		ev.err = ErrReservedKey
+		return ev
+	}
+	value, origin, found := ev.genv.resolve(newKey)
+	switch {
+	case found && ev.found:
+		ev.genv.log(slog.LevelWarn, "deprecated variable ignored in favor of its replacement",
+			"key", ev.key,
+			"replacement", newKey,
+		)
+	case ev.found:
+		ev.genv.log(slog.LevelWarn, "deprecated variable used; set its replacement instead",
+			"key", ev.key,
+			"replacement", newKey,
+		)
+		return ev
+	case !found:
+		ev.key = newKey
+		return ev
+	}
+
+	ev.key, ev.value, ev.found, ev.origin = newKey, value, found, origin
+	return ev
+}
+
+// Where the value of a variable came from, as reported by Genv.Provenance.
+type Origin string
+
+const (
+	// The process environment.
+	OriginEnv Origin = "env"
+	// A Source given with WithSource or WithSources, other than EnvSource.
+	OriginSource Origin = "source"
+	// A file loaded with WithConfigFile or WithDotEnv.
+	OriginFile Origin = "file"
+	// A default, such as one given to Default.
+	OriginDefault Origin = "default"
+	// Nowhere; the variable is absent.
+	OriginUnset Origin = ""
+)
+
+// Returns where the value of the variable came from.
+func (ev *Var) Origin() Origin {
+	return ev.origin
+}
+
+// Requires the variable to be set, while allowing it to be set to an empty
+// string, which then parses to the zero value. Parsing fails with
+// ErrUnsetEnvironmentVariable if the variable is unset; a Default does not
+// count as setting it.
+func (ev *Var) RequirePresent() *Var {
+	ev.mustBeSet = true
+	if !ev.found && !ev.skipped && ev.err == nil {
+		ev.err = ErrUnsetEnvironmentVariable
+	}
+	return ev
+}
+
+// Parses the variable only when the variable with the given key is set to
+// value, such as REDIS_URL only when CACHE_BACKEND=redis. Otherwise the
+// variable is skipped: it parses to the zero value without failing, whatever
+// is called on it before or after, such as Default or RequirePresent.
+func (ev *Var) OnlyIf(key, value string) *Var {
+	if actual, _ := ev.genv.lookup(ev.genv.prefix + key); actual != value {
+		ev.skipped = true
+		ev.value, ev.origin, ev.err = "", OriginUnset, nil
+	}
+	return ev
+}
+
+// Prefers the value loaded from a config file over the environment for this
+// variable only. Has no effect unless a config file provides the variable.
+func (ev *Var) PreferFile() *Var {
+	if ev.skipped {
+		return ev
+	}
+	if value, found := ev.genv.lookupFile(ev.key); found {
+		ev.value, ev.found, ev.origin = value, true, OriginFile
+	}
+	return ev
+}
+
 // Sets the default value for the environment variable if not present
 func (ev *Var) Default(value string, opts ...defaultOpt) *Var {
+	return ev.DefaultFunc(func(*Genv) string { return value }, opts...)
+}
+
+// Sets the default value for the environment variable if not present,
+// computing it with fn only when the default is actually used. This suits
+// defaults derived from the clock (see Genv.Now) or other variables.
+func (ev *Var) DefaultFunc(fn func(*Genv) string, opts ...defaultOpt) *Var {
+	ev.hasDefault = true
 	fb := new(fallback)
-	fb.allow = ev.allowDefault
+	if ev.genv == nil || !ev.genv.strict {
+		fb.allow = ev.allowDefault
+	}
 
 	for _, opt := range opts {
 		opt(fb)
 	}
 
-	if !ev.found && fb.allow != nil && fb.allow(ev.genv) {
-		ev.value = value
+	if !ev.found && !ev.skipped && fb.allow != nil && fb.allow(ev.genv) {
+		ev.value = fn(ev.genv)
+		if ev.value != "" {
+			ev.origin = OriginDefault
+		}
 	}
 	return ev
 }
 
+// Sets the default value for the environment variable to the value of the
+// variable with the given key, which is only read if this one is not present.
+// The fallback is subject to the same rules as Default, and a missing
+// fallback leaves the variable absent.
+func (ev *Var) DefaultFromEnv(key string, opts ...defaultOpt) *Var {
+	if ev.found {
+		return ev
+	}
+	value, _ := ev.genv.lookup(ev.genv.prefix + key)
+	return ev.Default(value, opts...)
+}
+
 type manyOpt func(*Var)
 
 func (genv *Genv) WithSplitKey(splitKey string) manyOpt {
@@ -117,22 +524,169 @@ func (genv *Genv) WithSplitKey(splitKey string) manyOpt {
 	}
 }
 
+// Accepts values written either as a delimited list or as a JSON array,
+// detected by a leading "[". This tolerates producers that disagree on how
+// lists are encoded, so that `a,b` and `["a","b"]` parse the same way.
+func (genv *Genv) WithFlexibleList() manyOpt {
+	return func(mev *Var) {
+		mev.flexibleList = true
+	}
+}
+
+// Picks the delimiter for each value from among ",", ";", and "|" by how
+// often each occurs, trimming whitespace around the elements, or splits on
+// whitespace when none of them occurs. This is a heuristic meant to tolerate
+// inconsistent producers: when the most frequent candidates tie, or the
+// value has no delimiter at all, the configured split key is used instead.
+func (genv *Genv) WithAutoDelimiter() manyOpt {
+	return func(mev *Var) {
+		mev.autoDelimiter = true
+	}
+}
+
+// Supplies a default for each position of a fixed-arity list, such as
+// thresholds written ",50," that take their first and last elements from
+// the defaults. Empty positions are filled from vals, subject to the same
+// allow-default rules as Default, and elements keep their positions rather
+// than empty ones being skipped.
+func (genv *Genv) WithDefaultsPerElement(vals ...string) manyOpt {
+	return func(mev *Var) {
+		mev.elemDefaults = vals
+	}
+}
+
+// Trims leading and trailing whitespace from each element before it is
+// parsed, so that "a, b, c" splits into "a", "b", and "c". Elements left
+// empty are skipped like any other empty element.
+func (genv *Genv) WithTrimSpace() manyOpt {
+	return func(mev *Var) {
+		mev.trimSpace = true
+	}
+}
+
+// Drops repeated elements, keeping the first occurrence of each in order.
+// Elements are compared after parsing, so with CaseFold and OneOf, "Auth"
+// and "auth" are the same element. Elements must be of a comparable type,
+// and fail to parse otherwise, as do interface elements holding values that
+// cannot be compared. Pointers, such as *url.URL, are compared by identity,
+// so elements that point to equal values are all kept.
+func (genv *Genv) WithUnique() manyOpt {
+	return func(mev *Var) {
+		mev.unique = true
+	}
+}
+
+// Sorts the parsed elements in their natural order. Only elements of
+// string, integer, float, and duration types, or types based on them, are
+// supported; other types fail to parse.
+func (genv *Genv) WithSorted() manyOpt {
+	return func(mev *Var) {
+		mev.sorted = true
+	}
+}
+
+// Limits the number of elements a value may be split into, counting empty
+// ones. Values with more elements fail before they are split, so that
+// oversized input is rejected cheaply.
+func (genv *Genv) WithMaxElements(n int) manyOpt {
+	return func(mev *Var) {
+		mev.maxElements = n
+	}
+}
+
+// Sets the separator between the key and value of each element parsed as
+// a pair, such as by OrderedPairs or StringMap. Defaults to "=".
+func (genv *Genv) WithPairSeparator(sep string) manyOpt {
+	return func(mev *Var) {
+		mev.pairSep = sep
+	}
+}
+
+// Supplies the elements to use when the value has none. Unlike a string
+// Default, the elements are used as given rather than being split. The
+// defaults are subject to the same allow-default rules as Default.
+func (genv *Genv) WithDefaultElements(vals ...string) manyOpt {
+	return func(mev *Var) {
+		mev.defaultElems = vals
+	}
+}
+
 func (ev *Var) String() string {
-	return mustParse(ev, (*Var).parseString)
+	return mustParse(ev, (*Var).TryString)
 }
 
 func (ev *Var) ManyString(opts ...manyOpt) []string {
-	return mustParseMany(ev, (*Var).parseString, opts...)
+	return mustParseMany(ev, (*Var).TryString, opts...)
+}
+
+func (ev *Var) TryManyString(opts ...manyOpt) ([]string, error) {
+	return parseMany(ev, (*Var).TryString, opts...)
 }
 
-func (ev *Var) parseString() (string, error) {
+func (ev *Var) TryString() (string, error) {
 	return parse(ev, func(value string) (string, error) {
+		if ev.charset != "" {
+			if i := strings.IndexFunc(value, func(r rune) bool {
+				return !strings.ContainsRune(ev.charset, r)
+			}); i >= 0 {
+				r, _ := utf8.DecodeRuneInString(value[i:])
+				return "", fmt.Errorf("%q contains the disallowed character %q", value, r)
+			}
+		}
+		if ev.glob != "" {
+			matched, err := path.Match(ev.glob, value)
+			if err != nil {
+				return "", err
+			}
+			if !matched {
+				return "", fmt.Errorf("%q does not match the pattern %q", value, ev.glob)
+			}
+		}
 		return value, nil
 	})
 }
 
+// Requires string values to consist only of characters in allowed.
+func (ev *Var) CharsetAllowed(allowed string) *Var {
+	ev.charset = allowed
+	return ev
+}
+
+// Requires string values to match the given glob pattern, such as
+// "*.example.com". Patterns follow the syntax of path.Match.
+func (ev *Var) MatchGlob(pattern string) *Var {
+	ev.glob = pattern
+	return ev
+}
+
 func (ev *Var) TryBool() (bool, error) {
-	return parse(ev, strconv.ParseBool)
+	return parse(ev, func(value string) (bool, error) {
+		if ev.genv != nil && ev.genv.boolTokens != nil {
+			if result, ok := ev.genv.boolTokens[strings.ToLower(value)]; ok {
+				return result, nil
+			}
+		}
+		if ev.boolLoose {
+			if result, ok := looseBools[strings.ToLower(value)]; ok {
+				return result, nil
+			}
+		}
+		return strconv.ParseBool(value)
+	})
+}
+
+// Booleans accepted by BoolLoose, keyed by their lowercase spelling.
+var looseBools = map[string]bool{
+	"true": true, "yes": true, "y": true, "on": true, "enabled": true,
+	"false": false, "no": false, "n": false, "off": false, "disabled": false,
+}
+
+// Accepts the common spellings yes/no, y/n, on/off, and enabled/disabled as
+// booleans, in any case, in addition to those understood by
+// strconv.ParseBool.
+func (ev *Var) BoolLoose() *Var {
+	ev.boolLoose = true
+	return ev
 }
 
 func (ev *Var) Bool() bool {
@@ -152,7 +706,18 @@ func (ev *Var) Int() int {
 }
 
 func (ev *Var) TryInt() (int, error) {
-	return parse(ev, strconv.Atoi)
+	return parse(ev, func(value string) (int, error) {
+		result, err := strconv.Atoi(value)
+		return saturate(ev, result, err)
+	})
+}
+
+// Clamps integers that overflow their type to its minimum or maximum
+// value instead of failing to parse them. Negative values clamp to 0 for
+// unsigned types.
+func (ev *Var) Saturate() *Var {
+	ev.saturating = true
+	return ev
 }
 
 func (ev *Var) TryManyInt(opts ...manyOpt) ([]int, error) {
@@ -163,126 +728,1750 @@ func (ev *Var) ManyInt(opts ...manyOpt) []int {
 	return mustParseMany(ev, (*Var).TryInt, opts...)
 }
 
-func (ev *Var) Float64() float64 {
-	return mustParse(ev, (*Var).TryFloat64)
+func (ev *Var) Int64() int64 {
+	return mustParse(ev, (*Var).TryInt64)
 }
 
-func (ev *Var) TryFloat64() (float64, error) {
-	return parse(ev, func(value string) (float64, error) {
-		return strconv.ParseFloat(value, 64)
+func (ev *Var) TryInt64() (int64, error) {
+	return parse(ev, func(value string) (int64, error) {
+		result, err := strconv.ParseInt(value, 10, 64)
+		return saturate(ev, result, err)
 	})
 }
 
-func (ev *Var) TryManyFloat64(opts ...manyOpt) ([]float64, error) {
-	return parseMany(ev, (*Var).TryFloat64, opts...)
+func (ev *Var) Int32() int32 {
+	return mustParse(ev, (*Var).TryInt32)
 }
 
-func (ev *Var) ManyFloat64(opts ...manyOpt) []float64 {
-	return mustParseMany(ev, (*Var).TryFloat64, opts...)
+func (ev *Var) TryInt32() (int32, error) {
+	return parse(ev, func(value string) (int32, error) {
+		result, err := strconv.ParseInt(value, 10, 32)
+		return saturate(ev, int32(result), err)
+	})
 }
 
-// Returns the value of the environment variable as a URL.
-// Panics if the value is not a valid URL, but this may happen
-// if a scheme is not specified. See the documentation for
-// url.Parse for more information.
-func (ev *Var) URL() *url.URL {
-	return mustParse(ev, (*Var).TryURL)
+func (ev *Var) TryManyInt32(opts ...manyOpt) ([]int32, error) {
+	return parseMany(ev, (*Var).TryInt32, opts...)
 }
 
-// Returns the value of the environment variable as a URL.
-// Fails if the value is not a valid URL, but this may happen
-// if a scheme is not specified. See the documentation for
-// url.Parse for more information.
-func (ev *Var) TryURL() (*url.URL, error) {
-	return parse(ev, url.Parse)
+func (ev *Var) ManyInt32(opts ...manyOpt) []int32 {
+	return mustParseMany(ev, (*Var).TryInt32, opts...)
 }
 
-func (ev *Var) TryManyURL(opts ...manyOpt) ([]*url.URL, error) {
-	return parseMany(ev, (*Var).TryURL, opts...)
+func (ev *Var) TryManyInt64(opts ...manyOpt) ([]int64, error) {
+	return parseMany(ev, (*Var).TryInt64, opts...)
 }
 
-func (ev *Var) ManyURL(opts ...manyOpt) []*url.URL {
-	return mustParseMany(ev, (*Var).TryURL, opts...)
+func (ev *Var) ManyInt64(opts ...manyOpt) []int64 {
+	return mustParseMany(ev, (*Var).TryInt64, opts...)
 }
 
-// Returns true if the environment variable with the given key is set and non-empty
-func (genv *Genv) Present(key string) bool {
-	result := genv.Var(key).Optional().String()
-	return result != ""
+func (ev *Var) Uint() uint {
+	return mustParse(ev, (*Var).TryUint)
 }
 
-const errFmtInvalidVar = "%s is invalid: %w"
-
-func parse[T any](ev *Var, fn func(string) (T, error)) (T, error) {
+func (ev *Var) TryUint() (uint, error) {
+	return parse(ev, func(value string) (uint, error) {
+		result, err := ev.parseUint(value, strconv.IntSize)
+		return uint(result), err
+	})
+}
 
-	var result T
-	var err error
+func (ev *Var) TryManyUint(opts ...manyOpt) ([]uint, error) {
+	return parseMany(ev, (*Var).TryUint, opts...)
+}
 
-	if !ev.optional && ev.value == "" {
-		return result, fmt.Errorf(errFmtInvalidVar, ev.key, ErrRequiredEnvironmentVariable)
-	}
+func (ev *Var) ManyUint(opts ...manyOpt) []uint {
+	return mustParseMany(ev, (*Var).TryUint, opts...)
+}
 
-	if ev.value == "" {
-		// If validation succeeded, then the value being empty means it was
-		// optional (or just an empty string is the desired output).
-		// In that case, use the zero value.
-		return result, nil
-	}
+func (ev *Var) Uint64() uint64 {
+	return mustParse(ev, (*Var).TryUint64)
+}
 
-	result, err = fn(ev.value)
-	if err != nil {
-		return result, fmt.Errorf(errFmtInvalidVar, ev.key, err)
-	}
-	return result, nil
+func (ev *Var) TryUint64() (uint64, error) {
+	return parse(ev, func(value string) (uint64, error) {
+		return ev.parseUint(value, 64)
+	})
 }
 
-func mustParse[T any](ev *Var, fn func(*Var) (T, error)) T {
-	result, err := fn(ev)
-	if err != nil {
-		panic(err)
+// Parses an unsigned integer, clamping it to the range of bitSize bits when
+// saturation is enabled, including negative values to 0.
+func (ev *Var) parseUint(value string, bitSize int) (uint64, error) {
+	result, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil && ev.saturating && strings.HasPrefix(value, "-") {
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+			return 0, nil
+		}
 	}
-	return result
+	return saturate(ev, result, err)
 }
 
-var ErrRequiredEnvironmentVariable = errors.New("environment variable is empty or unset")
+func (ev *Var) TryManyUint64(opts ...manyOpt) ([]uint64, error) {
+	return parseMany(ev, (*Var).TryUint64, opts...)
+}
 
-func parseMany[T any](ev *Var, fn func(*Var) (T, error), opts ...manyOpt) ([]T, error) {
-	for _, opt := range opts {
-		opt(ev)
-	}
+func (ev *Var) ManyUint64(opts ...manyOpt) []uint64 {
+	return mustParseMany(ev, (*Var).TryUint64, opts...)
+}
 
-	if ev.splitKey == "" {
-		return nil, errors.New("split key cannot be empty")
-	}
+// Returns the value of the environment variable as exactly three integers,
+// such as an RGB triple. Panics if the value does not hold three elements.
+func (ev *Var) ManyInt3(opts ...manyOpt) [3]int {
+	return mustParse(ev, func(ev *Var) ([3]int, error) {
+		return ev.TryManyInt3(opts...)
+	})
+}
 
-	split := strings.Split(ev.value, ev.splitKey)
-	vars := make([]Var, 0, len(split))
-	for _, val := range split {
-		if val == "" {
-			continue
-		}
-		vars = append(vars, Var{
-			key:          ev.key,
-			value:        val,
-			found:        ev.found,
-			optional:     ev.optional,
-			allowDefault: ev.allowDefault,
-			genv:         ev.genv,
-		})
+// Returns the value of the environment variable as exactly three integers,
+// such as an RGB triple. Fails if the value does not hold three elements.
+func (ev *Var) TryManyInt3(opts ...manyOpt) ([3]int, error) {
+	var result [3]int
+	values, err := ev.TryManyInt(opts...)
+	if err != nil || len(values) == 0 {
+		return result, err
 	}
-	if !ev.optional && len(vars) == 0 {
-		return nil, fmt.Errorf(errFmtInvalidVar, ev.key, ErrRequiredEnvironmentVariable)
+	if len(values) != len(result) {
+		return result, ev.wrapErr(
+			fmt.Errorf("expected %d elements, got %d", len(result), len(values)))
 	}
+	copy(result[:], values)
+	return result, nil
+}
 
-	result := make([]T, len(vars))
-	for i, ev := range vars {
-		val, err := fn(&ev)
+func (ev *Var) Port() int {
+	return mustParse(ev, (*Var).TryPort)
+}
+
+// Returns the value of the environment variable as a TCP or UDP port
+// number. Fails if the value is not an integer from 1 to 65535.
+func (ev *Var) TryPort() (int, error) {
+	return parse(ev, func(value string) (int, error) {
+		port, err := strconv.Atoi(value)
 		if err != nil {
-			return nil, fmt.Errorf(errFmtInvalidVar, ev.key, err)
+			return 0, err
 		}
-		result[i] = val
-	}
-	return result, nil
+		if port < 1 || port > 65535 {
+			return 0, fmt.Errorf("port %d is out of range [1, 65535]", port)
+		}
+		return port, nil
+	})
+}
+
+func (ev *Var) TryManyPort(opts ...manyOpt) ([]int, error) {
+	return parseMany(ev, (*Var).TryPort, opts...)
+}
+
+func (ev *Var) ManyPort(opts ...manyOpt) []int {
+	return mustParseMany(ev, (*Var).TryPort, opts...)
+}
+
+func (ev *Var) Float64() float64 {
+	return mustParse(ev, (*Var).TryFloat64)
+}
+
+func (ev *Var) TryFloat64() (float64, error) {
+	return parse(ev, ev.parseFloat64)
+}
+
+func (ev *Var) parseFloat64(value string) (float64, error) {
+	return ev.parseFloat(value, 64)
+}
+
+func (ev *Var) parseFloat(value string, bitSize int) (float64, error) {
+	if ev.decimalComma {
+		if strings.Contains(value, ".") || strings.Count(value, ",") > 1 {
+			return 0, fmt.Errorf("%q is not a number with a decimal comma", value)
+		}
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	return strconv.ParseFloat(value, bitSize)
+}
+
+// Treats "," as the decimal separator when parsing floats, so that "1,5"
+// parses as 1.5. Thousands separators are not accepted. Lists of such values
+// must be given a split key other than ",".
+func (ev *Var) DecimalComma() *Var {
+	ev.decimalComma = true
+	return ev
+}
+
+func (ev *Var) Float32() float32 {
+	return mustParse(ev, (*Var).TryFloat32)
+}
+
+// Fails if the value is out of the range of a float32, rather than
+// rounding it to an infinity.
+func (ev *Var) TryFloat32() (float32, error) {
+	return parse(ev, func(value string) (float32, error) {
+		result, err := ev.parseFloat(value, 32)
+		return float32(result), err
+	})
+}
+
+func (ev *Var) TryManyFloat32(opts ...manyOpt) ([]float32, error) {
+	return parseMany(ev, (*Var).TryFloat32, opts...)
+}
+
+func (ev *Var) ManyFloat32(opts ...manyOpt) []float32 {
+	return mustParseMany(ev, (*Var).TryFloat32, opts...)
+}
+
+func (ev *Var) TryManyFloat64(opts ...manyOpt) ([]float64, error) {
+	return parseMany(ev, (*Var).TryFloat64, opts...)
+}
+
+func (ev *Var) ManyFloat64(opts ...manyOpt) []float64 {
+	return mustParseMany(ev, (*Var).TryFloat64, opts...)
+}
+
+// Sets the scheme to prepend to URL values that do not specify one,
+// so that a value like "example.com:8080" parses with its host and port
+// populated rather than being misread as a scheme.
+func (ev *Var) DefaultScheme(scheme string) *Var {
+	ev.defaultScheme = scheme
+	return ev
+}
+
+// Requires URL values to carry each of the given query parameters, such
+// as the token of a webhook URL. A parameter given with an empty value
+// counts as present.
+func (ev *Var) RequireQueryParams(keys ...string) *Var {
+	ev.queryParams = append(ev.queryParams, keys...)
+	return ev
+}
+
+// Returns the value of the environment variable, an integer number of
+// basis points, as a fraction: "150" is 1.50%, or 0.015. Panics if the
+// value is not an integer, or with BoundBasisPoints, not between 0 and
+// 10000.
+func (ev *Var) BasisPoints() float64 {
+	return mustParse(ev, (*Var).TryBasisPoints)
+}
+
+// Returns the value of the environment variable, an integer number of
+// basis points, as a fraction: "150" is 1.50%, or 0.015. Fails if the
+// value is not an integer, or with BoundBasisPoints, not between 0 and
+// 10000.
+func (ev *Var) TryBasisPoints() (float64, error) {
+	return parse(ev, func(value string) (float64, error) {
+		bps, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, err
+		}
+		if ev.bpsBounded && (bps < 0 || bps > 10000) {
+			return 0, fmt.Errorf("%d basis points is outside the range [0, 10000]", bps)
+		}
+		return float64(bps) / 10000, nil
+	})
+}
+
+// Requires basis points to be between 0 and 10000, that is, a fraction
+// between 0 and 1, such as for a fee that can be neither negative nor more
+// than the whole amount.
+func (ev *Var) BoundBasisPoints() *Var {
+	ev.bpsBounded = true
+	return ev
+}
+
+// Returns the value of the environment variable as an exact fraction,
+// written either as a ratio like "1/3" or a decimal like "0.5".
+// Panics if the value is malformed or has a zero denominator.
+func (ev *Var) Rat() *big.Rat {
+	return mustParse(ev, (*Var).TryRat)
+}
+
+// Returns the value of the environment variable as an exact fraction,
+// written either as a ratio like "1/3" or a decimal like "0.5".
+// Fails if the value is malformed or has a zero denominator.
+func (ev *Var) TryRat() (*big.Rat, error) {
+	return parse(ev, func(value string) (*big.Rat, error) {
+		if _, denom, ok := strings.Cut(value, "/"); ok {
+			if d, ok := new(big.Int).SetString(denom, 10); ok && d.Sign() == 0 {
+				return nil, fmt.Errorf("%q has a zero denominator", value)
+			}
+		}
+		result, ok := new(big.Rat).SetString(value)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a valid fraction", value)
+		}
+		return result, nil
+	})
+}
+
+// A pair of floats read from a single value, such as a latitude
+// and longitude.
+type Float64Pair struct {
+	A, B float64
+}
+
+// Returns the value of the environment variable as two floats separated
+// by sep, such as "37.77,-122.42". Panics if the value is not such a pair.
+func (ev *Var) Float64Pair(sep string) Float64Pair {
+	return mustParse(ev, func(ev *Var) (Float64Pair, error) {
+		return ev.TryFloat64Pair(sep)
+	})
+}
+
+// Returns the value of the environment variable as two floats separated
+// by sep, such as "37.77,-122.42". Fails unless the value has exactly two
+// components and both are valid floats.
+func (ev *Var) TryFloat64Pair(sep string) (Float64Pair, error) {
+	return parse(ev, func(value string) (Float64Pair, error) {
+		parts := strings.Split(value, sep)
+		if len(parts) != 2 {
+			return Float64Pair{}, fmt.Errorf("expected 2 components, got %d", len(parts))
+		}
+
+		var pair Float64Pair
+		var err error
+		if pair.A, err = ev.parseFloat64(parts[0]); err != nil {
+			return Float64Pair{}, err
+		}
+		if pair.B, err = ev.parseFloat64(parts[1]); err != nil {
+			return Float64Pair{}, err
+		}
+		return pair, nil
+	})
+}
+
+// Returns the value of the environment variable as a URL.
+// Panics if the value is not a valid URL, but this may happen
+// if a scheme is not specified (see DefaultScheme). See the
+// documentation for url.Parse for more information.
+func (ev *Var) URL() *url.URL {
+	return mustParse(ev, (*Var).TryURL)
+}
+
+// Returns the value of the environment variable as a URL.
+// Fails if the value is not a valid URL, but this may happen
+// if a scheme is not specified (see DefaultScheme). See the
+// documentation for url.Parse for more information.
+func (ev *Var) TryURL() (*url.URL, error) {
+	return parse(ev, func(value string) (*url.URL, error) {
+		if ev.defaultScheme != "" && !strings.Contains(value, "://") {
+			value = ev.defaultScheme + "://" + value
+		}
+		u, err := url.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		query := u.Query()
+		for _, key := range ev.queryParams {
+			if !query.Has(key) {
+				return nil, fmt.Errorf("missing required query parameter %q", key)
+			}
+		}
+		return u, nil
+	})
+}
+
+// Returns the value of the environment variable as a URL along with a
+// form of it that is safe to log, with any userinfo removed. Panics if the
+// value is not a valid URL.
+func (ev *Var) URLRedacted() (full *url.URL, safe string) {
+	full, safe, err := ev.TryURLRedacted()
+	if err != nil {
+		panic(err)
+	}
+	return full, safe
+}
+
+// Returns the value of the environment variable as a URL along with a
+// form of it that is safe to log, with any userinfo, such as a password,
+// removed. An optional variable that is absent yields a nil URL and "".
+func (ev *Var) TryURLRedacted() (full *url.URL, safe string, err error) {
+	full, err = ev.TryURL()
+	if err != nil || full == nil {
+		return full, "", err
+	}
+	stripped := *full
+	stripped.User = nil
+	return full, stripped.String(), nil
+}
+
+func (ev *Var) TryManyURL(opts ...manyOpt) ([]*url.URL, error) {
+	return parseMany(ev, (*Var).TryURL, opts...)
+}
+
+func (ev *Var) ManyURL(opts ...manyOpt) []*url.URL {
+	return mustParseMany(ev, (*Var).TryURL, opts...)
+}
+
+func (ev *Var) Bytes() []byte {
+	return mustParse(ev, (*Var).TryBytes)
+}
+
+// Returns the value of the environment variable decoded from base64, such
+// as a TLS key or HMAC secret. Uses base64.StdEncoding unless another
+// encoding is given with Base64Encoding. An optional variable that is absent
+// yields a nil slice.
+func (ev *Var) TryBytes() ([]byte, error) {
+	return parse(ev, func(value string) ([]byte, error) {
+		encoding := ev.encoding
+		if encoding == nil {
+			encoding = base64.StdEncoding
+		}
+		return encoding.DecodeString(value)
+	})
+}
+
+// Sets the encoding used by Bytes, such as base64.RawURLEncoding.
+func (ev *Var) Base64Encoding(encoding *base64.Encoding) *Var {
+	ev.encoding = encoding
+	return ev
+}
+
+func (ev *Var) HexBytes() []byte {
+	return mustParse(ev, (*Var).TryHexBytes)
+}
+
+// Returns the value of the environment variable decoded from hexadecimal,
+// in either case. An optional variable that is absent yields a nil slice.
+func (ev *Var) TryHexBytes() ([]byte, error) {
+	return parse(ev, hex.DecodeString)
+}
+
+func (ev *Var) IP() net.IP {
+	return mustParse(ev, (*Var).TryIP)
+}
+
+// Accepts IPv4 and IPv6 addresses, as understood by net.ParseIP.
+func (ev *Var) TryIP() (net.IP, error) {
+	return parse(ev, func(value string) (net.IP, error) {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, &net.ParseError{Type: "IP address", Text: value}
+		}
+		return ip, nil
+	})
+}
+
+func (ev *Var) TryManyIP(opts ...manyOpt) ([]net.IP, error) {
+	return parseMany(ev, (*Var).TryIP, opts...)
+}
+
+func (ev *Var) ManyIP(opts ...manyOpt) []net.IP {
+	return mustParseMany(ev, (*Var).TryIP, opts...)
+}
+
+func (ev *Var) CIDR() *net.IPNet {
+	return mustParse(ev, (*Var).TryCIDR)
+}
+
+// Accepts CIDR blocks such as 10.0.0.0/8, as understood by net.ParseCIDR.
+// The result is the network itself, so host bits in the value are dropped.
+func (ev *Var) TryCIDR() (*net.IPNet, error) {
+	return parse(ev, func(value string) (*net.IPNet, error) {
+		_, network, err := net.ParseCIDR(value)
+		return network, err
+	})
+}
+
+func (ev *Var) TryManyCIDR(opts ...manyOpt) ([]*net.IPNet, error) {
+	return parseMany(ev, (*Var).TryCIDR, opts...)
+}
+
+func (ev *Var) ManyCIDR(opts ...manyOpt) []*net.IPNet {
+	return mustParseMany(ev, (*Var).TryCIDR, opts...)
+}
+
+func (ev *Var) Duration() time.Duration {
+	return mustParse(ev, (*Var).TryDuration)
+}
+
+func (ev *Var) TryDuration() (time.Duration, error) {
+	return parse(ev, ev.parseDuration)
+}
+
+func (ev *Var) TryManyDuration(opts ...manyOpt) ([]time.Duration, error) {
+	return parseMany(ev, (*Var).TryDuration, opts...)
+}
+
+func (ev *Var) ManyDuration(opts ...manyOpt) []time.Duration {
+	return mustParseMany(ev, (*Var).TryDuration, opts...)
+}
+
+// Returns the elements of the environment variable as durations in
+// strictly ascending order, such as histogram buckets written
+// "5ms,10ms,25ms". Panics if the durations are not strictly ascending.
+func (ev *Var) DurationBuckets(opts ...manyOpt) []time.Duration {
+	return mustParse(ev, func(ev *Var) ([]time.Duration, error) {
+		return ev.TryDurationBuckets(opts...)
+	})
+}
+
+// Returns the elements of the environment variable as durations in
+// strictly ascending order, such as histogram buckets written
+// "5ms,10ms,25ms". Fails naming the first element that is not greater
+// than the one before it.
+func (ev *Var) TryDurationBuckets(opts ...manyOpt) ([]time.Duration, error) {
+	buckets, err := ev.TryManyDuration(opts...)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return nil, ev.wrapErr(fmt.Errorf(
+				"element %d (%s) is not greater than the one before it (%s)",
+				i, buckets[i], buckets[i-1]))
+		}
+	}
+	return buckets, nil
+}
+
+// Accepts durations written as clock time, either HH:MM:SS or MM:SS,
+// in addition to the syntax understood by time.ParseDuration.
+func (ev *Var) ClockDuration() *Var {
+	ev.clockDuration = true
+	return ev
+}
+
+// Rounds parsed durations to the nearest multiple of d, such as
+// time.Millisecond, with halfway values rounded away from zero. Has no
+// effect if d is not positive.
+func (ev *Var) Round(d time.Duration) *Var {
+	ev.round = d
+	return ev
+}
+
+// Accepts durations phrased as "every 30s" in addition to plain durations.
+func (ev *Var) EveryPhrase() *Var {
+	ev.everyPhrase = true
+	return ev
+}
+
+func (ev *Var) parseDuration(value string) (time.Duration, error) {
+	if ev.everyPhrase {
+		value = strings.TrimPrefix(value, "every ")
+	}
+	var result time.Duration
+	var err error
+	if ev.clockDuration && strings.Contains(value, ":") {
+		result, err = parseClockDuration(value)
+	} else {
+		result, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.Round(ev.round), nil
+}
+
+// Returns the value of the environment variable as a duration clamped
+// to the range [lower, upper]. Panics if the value is not a valid duration.
+func (ev *Var) DurationClamp(lower, upper time.Duration) time.Duration {
+	return mustParse(ev, func(ev *Var) (time.Duration, error) {
+		return ev.TryDurationClamp(lower, upper)
+	})
+}
+
+// Returns the value of the environment variable as a duration clamped
+// to the range [lower, upper]. Out-of-range values are replaced by the
+// nearest bound and reported through the configured logger rather than
+// treated as an error.
+func (ev *Var) TryDurationClamp(lower, upper time.Duration) (time.Duration, error) {
+	if lower > upper {
+		return 0, ev.wrapErr(
+			fmt.Errorf("clamp lower bound %s exceeds upper bound %s", lower, upper))
+	}
+
+	result, err := ev.TryDuration()
+	if err != nil || ev.value == "" {
+		return result, err
+	}
+
+	clamped := min(max(result, lower), upper)
+	if clamped != result {
+		ev.genv.log(slog.LevelWarn, "clamped duration",
+			"key", ev.key,
+			"value", result,
+			"clamped", clamped,
+		)
+	}
+	return clamped, nil
+}
+
+// Returns the value of the environment variable decomposed as a connection
+// string. Panics if the value is not a valid connection string or its scheme
+// is not one of schemes.
+func (ev *Var) DSN(schemes ...string) DSNParts {
+	return mustParse(ev, func(ev *Var) (DSNParts, error) {
+		return ev.TryDSN(schemes...)
+	})
+}
+
+// Returns the value of the environment variable decomposed as a connection
+// string. Fails if the value is not a valid connection string or, when any
+// schemes are given, its scheme is not one of them.
+func (ev *Var) TryDSN(schemes ...string) (DSNParts, error) {
+	return parse(ev, func(value string) (DSNParts, error) {
+		return parseDSN(value, schemes)
+	})
+}
+
+// Returns the value of the environment variable as a filesystem path.
+// Panics if MustExist was set and nothing exists at the path.
+func (ev *Var) Path() string {
+	return mustParse(ev, (*Var).TryPath)
+}
+
+// Returns the value of the environment variable as a filesystem path.
+// Fails if MustExist was set and nothing exists at the path.
+func (ev *Var) TryPath() (string, error) {
+	return parse(ev, func(value string) (string, error) {
+		if ev.mustExist {
+			if _, err := os.Stat(value); err != nil {
+				return "", err
+			}
+		}
+		return value, nil
+	})
+}
+
+func (ev *Var) TryManyPath(opts ...manyOpt) ([]string, error) {
+	return parseMany(ev, (*Var).TryPath, opts...)
+}
+
+func (ev *Var) ManyPath(opts ...manyOpt) []string {
+	return mustParseMany(ev, (*Var).TryPath, opts...)
+}
+
+// Requires paths to exist on the filesystem. For lists, each element is
+// checked and the first missing path is reported.
+func (ev *Var) MustExist() *Var {
+	ev.mustExist = true
+	return ev
+}
+
+// Returns the network interface named by the environment variable.
+// Panics if no such interface exists.
+func (ev *Var) Interface() *net.Interface {
+	return mustParse(ev, (*Var).TryInterface)
+}
+
+// Returns the network interface named by the environment variable.
+// Fails if no such interface exists.
+func (ev *Var) TryInterface() (*net.Interface, error) {
+	return parse(ev, net.InterfaceByName)
+}
+
+// Returns the value of the environment variable as an identifier in the
+// given format. Panics if the value does not match the format.
+func (ev *Var) PrefixedID(format IDFormat) string {
+	return mustParse(ev, func(ev *Var) (string, error) {
+		return ev.TryPrefixedID(format)
+	})
+}
+
+// Returns the value of the environment variable as an identifier in the
+// given format. Fails if the value does not match the format.
+func (ev *Var) TryPrefixedID(format IDFormat) (string, error) {
+	return parse(ev, format.parse)
+}
+
+// Returns the value of the environment variable as a compiled regular
+// expression. Panics if the value is not a valid expression.
+func (ev *Var) Regexp() *regexp.Regexp {
+	return mustParse(ev, (*Var).TryRegexp)
+}
+
+// Returns the value of the environment variable as a compiled regular
+// expression. Fails if the value is not a valid expression.
+func (ev *Var) TryRegexp() (*regexp.Regexp, error) {
+	return parse(ev, regexp.Compile)
+}
+
+func (ev *Var) TryManyRegexp(opts ...manyOpt) ([]*regexp.Regexp, error) {
+	return parseMany(ev, (*Var).TryRegexp, opts...)
+}
+
+func (ev *Var) ManyRegexp(opts ...manyOpt) []*regexp.Regexp {
+	return mustParseMany(ev, (*Var).TryRegexp, opts...)
+}
+
+// Returns the value of the environment variable as a time, trying each of
+// layouts in order and defaulting to time.RFC3339 when none are given.
+// Panics if no layout matches.
+func (ev *Var) Time(layouts ...string) time.Time {
+	return mustParse(ev, func(ev *Var) (time.Time, error) {
+		return ev.TryTime(layouts...)
+	})
+}
+
+// Returns the value of the environment variable as a time, trying each of
+// layouts in order and using the first that parses. Defaults to
+// time.RFC3339 when no layouts are given. Fails if no layout matches.
+func (ev *Var) TryTime(layouts ...string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	return parse(ev, func(value string) (time.Time, error) {
+		for _, layout := range layouts {
+			if result, err := time.Parse(layout, value); err == nil {
+				return result, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("%q does not match any of the layouts %q", value, layouts)
+	})
+}
+
+// Returns the value of the environment variable as key=value pairs in the
+// order they were written, such as "auth=1,log=2". Panics if an element is
+// not a pair or a key is repeated.
+func (ev *Var) OrderedPairs(opts ...manyOpt) OrderedPairs {
+	return mustParse(ev, func(ev *Var) (OrderedPairs, error) {
+		return ev.TryOrderedPairs(opts...)
+	})
+}
+
+// Returns the value of the environment variable as key=value pairs in the
+// order they were written, such as "auth=1,log=2". Fails if an element is
+// not a pair or a key is repeated.
+func (ev *Var) TryOrderedPairs(opts ...manyOpt) (OrderedPairs, error) {
+	pairs, err := parseMany(ev, (*Var).tryPair, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUniqueKeys(pairs); err != nil {
+		return nil, ev.wrapErr(err)
+	}
+	return pairs, nil
+}
+
+func (ev *Var) tryPair() (Pair, error) {
+	return parse(ev, func(value string) (Pair, error) {
+		sep := ev.pairSep
+		if sep == "" {
+			sep = "="
+		}
+		key, val, ok := strings.Cut(value, sep)
+		if !ok || key == "" {
+			return Pair{}, fmt.Errorf("%q is not a key%svalue pair", value, sep)
+		}
+		return Pair{Key: key, Value: val}, nil
+	})
+}
+
+// Returns the value of the environment variable as a map written as
+// key=value pairs, such as "a=1,b=2". Panics if an element is not a pair or
+// a key is repeated.
+func (ev *Var) StringMap(opts ...manyOpt) map[string]string {
+	return mustParse(ev, func(ev *Var) (map[string]string, error) {
+		return ev.TryStringMap(opts...)
+	})
+}
+
+// Returns the value of the environment variable as a map written as
+// key=value pairs, such as "a=1,b=2". An optional variable that is absent
+// yields a nil map.
+func (ev *Var) TryStringMap(opts ...manyOpt) (map[string]string, error) {
+	return TryMap(ev, (*Var).TryString, opts...)
+}
+
+// Returns the value of the environment variable as a map written as
+// key=value pairs, parsing each value with fn, such as (*Var).TryInt.
+// Panics if an element is not a pair, a key is repeated, or a value fails
+// to parse.
+func Map[V any](ev *Var, fn func(*Var) (V, error), opts ...manyOpt) map[string]V {
+	return mustParse(ev, func(ev *Var) (map[string]V, error) {
+		return TryMap(ev, fn, opts...)
+	})
+}
+
+// Returns the value of the environment variable as a map written as
+// key=value pairs, parsing each value with fn, such as (*Var).TryInt. An
+// optional variable that is absent yields a nil map.
+func TryMap[V any](ev *Var, fn func(*Var) (V, error), opts ...manyOpt) (map[string]V, error) {
+	// Validators check each value, or the map as a whole, rather than the
+	// pairs they are read from. Any others are left to fn.
+	valueValidators, rest := validatorsOf(ev.validators, reflect.TypeFor[V]())
+	mapValidators, rest := validatorsOf(rest, reflect.TypeFor[map[string]V]())
+	pairsVar := *ev
+	pairsVar.validators = nil
+	pairs, err := pairsVar.TryOrderedPairs(opts...)
+	if err != nil || len(pairs) == 0 {
+		return nil, err
+	}
+
+	result := make(map[string]V, len(pairs))
+	// A pair such as "a=" is present, so its empty value yields the zero
+	// value of V rather than failing as unset.
+	elem := pairsVar
+	elem.optional = true
+	elem.validators = rest
+	for _, pair := range pairs {
+		elem.value = pair.Value
+		parsed, err := fn(&elem)
+		if err != nil {
+			return nil, ev.wrapErr(fmt.Errorf("key %q: %w", pair.Key, unwrapVarErr(err)))
+		}
+		if len(valueValidators) > 0 {
+			if parsed, err = validate(valueValidators, parsed); err != nil {
+				return nil, ev.wrapErr(fmt.Errorf("key %q: %w", pair.Key, err))
+			}
+		}
+		result[pair.Key] = parsed
+	}
+	if len(mapValidators) > 0 {
+		if result, err = validate(mapValidators, result); err != nil {
+			return nil, ev.wrapErr(err)
+		}
+	}
+	return result, nil
+}
+
+// Returns the value of the environment variable as integers keyed by
+// month, such as "Jan=10,Jul=15". Panics if a month or value is invalid.
+func (ev *Var) MonthMap(opts ...manyOpt) map[time.Month]int {
+	return mustParse(ev, func(ev *Var) (map[time.Month]int, error) {
+		return ev.TryMonthMap(opts...)
+	})
+}
+
+// Returns the value of the environment variable as integers keyed by
+// month, such as "Jan=10,Jul=15". Months may be written in full or as their
+// three-letter abbreviation, in any case. An optional variable that is
+// absent yields a nil map.
+func (ev *Var) TryMonthMap(opts ...manyOpt) (map[time.Month]int, error) {
+	ints, err := TryMap(ev, (*Var).TryInt, opts...)
+	if err != nil || ints == nil {
+		return nil, err
+	}
+
+	result := make(map[time.Month]int, len(ints))
+	for key, val := range ints {
+		month, err := parseMonth(key)
+		if err != nil {
+			return nil, ev.wrapErr(fmt.Errorf("key %q: %w", key, err))
+		}
+		if _, ok := result[month]; ok {
+			return nil, ev.wrapErr(fmt.Errorf("duplicate month %s", month))
+		}
+		result[month] = val
+	}
+	return result, nil
+}
+
+func parseMonth(value string) (time.Month, error) {
+	for month := time.January; month <= time.December; month++ {
+		name := month.String()
+		if strings.EqualFold(value, name) || strings.EqualFold(value, name[:3]) {
+			return month, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown month %q", value)
+}
+
+// One step of a polling schedule: wait Interval, Count times.
+type ScheduleStep struct {
+	Interval time.Duration
+	Count    int
+}
+
+// Returns the value of the environment variable as a schedule written as
+// interval:count pairs, such as "1s:3,5s:2" for 1s three times and then
+// 5s twice. Panics if any pair is malformed.
+func (ev *Var) Schedule(opts ...manyOpt) []ScheduleStep {
+	return mustParseMany(ev, (*Var).tryScheduleStep, opts...)
+}
+
+// Returns the value of the environment variable as a schedule written as
+// interval:count pairs, such as "1s:3,5s:2" for 1s three times and then
+// 5s twice. Fails if any pair is malformed or has a non-positive count.
+func (ev *Var) TrySchedule(opts ...manyOpt) ([]ScheduleStep, error) {
+	return parseMany(ev, (*Var).tryScheduleStep, opts...)
+}
+
+func (ev *Var) tryScheduleStep() (ScheduleStep, error) {
+	return parse(ev, func(value string) (ScheduleStep, error) {
+		interval, count, ok := strings.Cut(value, ":")
+		if !ok {
+			return ScheduleStep{}, fmt.Errorf("%q is not an interval:count pair", value)
+		}
+
+		var step ScheduleStep
+		var err error
+		if step.Interval, err = ev.parseDuration(interval); err != nil {
+			return ScheduleStep{}, err
+		}
+		if step.Count, err = strconv.Atoi(count); err != nil {
+			return ScheduleStep{}, err
+		}
+		if step.Count <= 0 {
+			return ScheduleStep{}, fmt.Errorf("count %d is not positive", step.Count)
+		}
+		return step, nil
+	})
+}
+
+// Returns the value of the environment variable as a version constraint,
+// such as ">=1.2.0 <2.0.0". Panics if the constraint is malformed.
+func (ev *Var) SemverConstraint() Constraint {
+	return mustParse(ev, (*Var).TrySemverConstraint)
+}
+
+// Returns the value of the environment variable as a version constraint,
+// such as ">=1.2.0 <2.0.0". Fails if the constraint is malformed.
+func (ev *Var) TrySemverConstraint() (Constraint, error) {
+	return parse(ev, ParseConstraint)
+}
+
+// Returns the value of the environment variable split into arguments
+// using shell-style quoting rules. Panics if the quoting is malformed.
+func (ev *Var) Args() []string {
+	return mustParse(ev, (*Var).TryArgs)
+}
+
+// Returns the value of the environment variable split into arguments
+// using shell-style quoting rules: whitespace separates arguments,
+// single quotes preserve their contents literally, and double quotes
+// allow backslash escapes. Fails if a quote is left unterminated or
+// the value ends with a dangling backslash.
+func (ev *Var) TryArgs() ([]string, error) {
+	return parse(ev, splitArgs)
+}
+
+// Returns the elements of the environment variable, each parsed with fn,
+// such as a function converting (*Var).TryString to an enum type. Panics if
+// the variable is required but empty or an element fails to parse.
+func Many[T any](ev *Var, fn func(*Var) (T, error), opts ...manyOpt) []T {
+	return mustParseMany(ev, fn, opts...)
+}
+
+// Returns the elements of the environment variable, each parsed with fn,
+// such as a function converting (*Var).TryString to an enum type. Element
+// failures are reported with their index, as for the other lists.
+func TryMany[T any](ev *Var, fn func(*Var) (T, error), opts ...manyOpt) ([]T, error) {
+	return parseMany(ev, fn, opts...)
+}
+
+// Returns the elements of the environment variable mapped through mapping.
+// Tokens missing from mapping are collected into unknown instead of failing,
+// so that values introduced by newer producers do not break older readers.
+// Panics if the variable is required but empty.
+func ManyChoiceLenient[T any](ev *Var, mapping map[string]T, opts ...manyOpt) (known []T, unknown []string) {
+	known, unknown, err := TryManyChoiceLenient(ev, mapping, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return known, unknown
+}
+
+// Returns the elements of the environment variable mapped through mapping.
+// Tokens missing from mapping are collected into unknown instead of failing,
+// so that values introduced by newer producers do not break older readers.
+func TryManyChoiceLenient[T any](ev *Var, mapping map[string]T, opts ...manyOpt) (known []T, unknown []string, err error) {
+	tokens, err := ev.TryManyString(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	known = make([]T, 0, len(tokens))
+	for _, token := range tokens {
+		if val, ok := lookupChoice(ev, mapping, token); ok {
+			known = append(known, val)
+		} else {
+			unknown = append(unknown, token)
+		}
+	}
+	return known, unknown, nil
+}
+
+// Returns the entry of mapping for token, ignoring case if the variable
+// uses CaseFold. An exact match is preferred.
+func lookupChoice[T any](ev *Var, mapping map[string]T, token string) (T, bool) {
+	if val, ok := mapping[token]; ok || !ev.caseFold {
+		return val, ok
+	}
+	for key, val := range mapping {
+		if strings.EqualFold(key, token) {
+			return val, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Checks that the value of valueKey does not also appear among the elements
+// of setKey, such as a NODE_ID that must not be one of the PEER_IDS. Both
+// variables are treated as optional by this check.
+func (genv *Genv) Unique(valueKey, setKey string) error {
+	ev := genv.newVar(valueKey).Optional()
+	value, err := ev.TryString()
+	if err != nil || value == "" {
+		return err
+	}
+
+	set, err := genv.newVar(setKey).Optional().TryManyString()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(set, value) {
+		return ev.wrapErr(fmt.Errorf("%q conflicts with an element of %s", value, genv.prefix+setKey))
+	}
+	return nil
+}
+
+// Checks that the variables with the given keys are either all present or
+// all absent, such as TLS_CERT and TLS_KEY. The error lists which of the
+// variables were set and which were missing.
+func (genv *Genv) AllOrNone(keys ...string) error {
+	var set, missing []string
+	for _, key := range keys {
+		key = genv.prefix + key
+		if value, _ := genv.lookup(key); value != "" {
+			set = append(set, key)
+		} else {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(set) > 0 && len(missing) > 0 {
+		return genv.wrapErr(fmt.Errorf("%s must be set together: set %s; missing %s",
+			strings.Join(append(set, missing...), ", "),
+			strings.Join(set, ", "),
+			strings.Join(missing, ", "),
+		))
+	}
+	return nil
+}
+
+// Adds a check that runs on values of type T once they have been parsed,
+// such as bounding an integer. T must be the type parsed from the value: a
+// func(int) error checks Int, or each element of ManyInt, while a
+// func([]int) error checks the list as a whole. Parsing as any other type
+// fails. Errors are reported under the key, and an optional variable that is
+// absent is not checked.
+func Validate[T any](ev *Var, fn func(T) error) *Var {
+	return addValidator(ev, func(value T) (T, error) {
+		return value, fn(value)
+	})
+}
+
+// Requires parsed values of type T to be one of allowed, such as a log
+// level, reporting the value and the allowed set otherwise. Like Validate,
+// parsing as a type other than T or a list of T fails. With CaseFold,
+// strings match regardless of case and yield the allowed value as written.
+func OneOf[T comparable](ev *Var, allowed ...T) *Var {
+	return addValidator(ev, func(value T) (T, error) {
+		if slices.Contains(allowed, value) {
+			return value, nil
+		}
+		// Compare by kind so that named string types, such as enums,
+		// fold as well.
+		if v := reflect.ValueOf(value); ev.caseFold && v.Kind() == reflect.String {
+			for _, candidate := range allowed {
+				c := reflect.ValueOf(candidate)
+				if c.Kind() == reflect.String && strings.EqualFold(v.String(), c.String()) {
+					return candidate, nil
+				}
+			}
+		}
+		return value, fmt.Errorf("%v is not one of %v", value, allowed)
+	})
+}
+
+// Adds fn to the validators of the variable, which may replace the value
+// as well as reject it.
+func addValidator[T any](ev *Var, fn func(T) (T, error)) *Var {
+	ev.validators = append(ev.validators, validator{
+		typ: reflect.TypeFor[T](),
+		fn: func(value any) (any, error) {
+			return fn(value.(T))
+		},
+	})
+	return ev
+}
+
+// Splits validators into those for values of type typ and the rest.
+func validatorsOf(validators []validator, typ reflect.Type) (matched, rest []validator) {
+	for _, v := range validators {
+		if v.typ == typ {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// Fails if any of validators is for a type other than typ, since it would
+// otherwise never run.
+func checkValidators(validators []validator, typ reflect.Type) error {
+	for _, v := range validators {
+		if v.typ != typ {
+			return fmt.Errorf("a validator for %s cannot check %s", v.typ, typ)
+		}
+	}
+	return nil
+}
+
+// Decodes the environment variable as JSON into a value of type V, such as
+// a struct. Panics if the value is not valid JSON for V.
+func JSON[V any](ev *Var) V {
+	return mustParse(ev, TryJSON[V])
+}
+
+// Decodes the environment variable as JSON into a value of type V. An
+// optional variable that is absent yields the zero value of V.
+func TryJSON[V any](ev *Var) (V, error) {
+	return parse(ev, func(value string) (V, error) {
+		var result V
+		err := json.Unmarshal([]byte(value), &result)
+		return result, err
+	})
+}
+
+// Parses the environment variable with fn and converts the result with
+// convert, such as mapping a region code to an endpoint URL.
+// Panics if either step fails.
+func As[T, R any](ev *Var, fn func(*Var) (T, error), convert func(T) (R, error)) R {
+	return mustParse(ev, func(ev *Var) (R, error) {
+		return TryAs(ev, fn, convert)
+	})
+}
+
+// Parses the environment variable with fn, such as (*Var).TryString, and
+// converts the result with convert. Validators check the result of fn, not
+// of convert. Errors from convert are wrapped with the key. An optional
+// variable that is absent yields the zero value of R without calling
+// convert.
+func TryAs[T, R any](ev *Var, fn func(*Var) (T, error), convert func(T) (R, error)) (R, error) {
+	var result R
+	val, err := fn(ev)
+	if err != nil || ev.value == "" {
+		return result, err
+	}
+
+	if result, err = convert(val); err != nil {
+		return result, ev.wrapErr(err)
+	}
+	return result, nil
+}
+
+// Returns true if the environment variable with the given key is set and
+// non-empty. Reserved keys are never present.
+func (genv *Genv) Present(key string) bool {
+	result, err := genv.newVar(key).Optional().TryString()
+	return err == nil && result != ""
+}
+
+// Looks up the raw value for key, consulting the environment first and then
+// any values loaded from a config file.
+func (genv *Genv) lookup(key string) (string, bool) {
+	value, _, found := genv.resolve(key)
+	return value, found
+}
+
+// Like lookup, but also reports where the value was found.
+func (genv *Genv) resolve(key string) (string, Origin, bool) {
+	if genv.reserved(key) {
+		return "", OriginUnset, false
+	}
+	if genv.preferFile {
+		if value, found := genv.lookupFile(key); found {
+			return value, OriginFile, true
+		}
+	}
+	if value, origin, found := genv.lookupEnv(key); found {
+		return value, origin, true
+	}
+	if !genv.preferFile {
+		if value, found := genv.lookupFile(key); found {
+			return value, OriginFile, true
+		}
+	}
+	return "", OriginUnset, false
+}
+
+func (genv *Genv) lookupEnv(key string) (string, Origin, bool) {
+	value, origin, found := "", OriginUnset, false
+	if genv.stripPrefix != "" {
+		value, origin, found = lookupOrigin(genv.source, genv.stripPrefix+key)
+	}
+	if !found {
+		value, origin, found = lookupOrigin(genv.source, key)
+	}
+	value, found = genv.checkSentinel(value, found)
+	return value, origin, found
+}
+
+func (genv *Genv) lookupFile(key string) (string, bool) {
+	value, found := genv.fileValues[key]
+	return genv.checkSentinel(value, found)
+}
+
+// Treats a value equal to the default sentinel as absent.
+func (genv *Genv) checkSentinel(value string, found bool) (string, bool) {
+	if found && genv.defaultSentinel != "" && value == genv.defaultSentinel {
+		return "", false
+	}
+	return value, found
+}
+
+func (genv *Genv) reserved(key string) bool {
+	_, reserved := genv.reservedKeys[key]
+	return reserved
+}
+
+// Records a declaration of the variable.
+func (r *registry) add(ev *Var) {
+	if r == nil {
+		return
+	}
+	r.vars = append(r.vars, ev)
+}
+
+// Returns the declared variables in declaration order. A key declared more
+// than once is reported with the value of its latest declaration, but stays
+// masked if any declaration masked it and required if any required it.
+func (r *registry) all() []*Var {
+	if r == nil {
+		return nil
+	}
+	var keys []string
+	declared := make(map[string][]*Var)
+	for _, ev := range r.vars {
+		if _, ok := declared[ev.key]; !ok {
+			keys = append(keys, ev.key)
+		}
+		declared[ev.key] = append(declared[ev.key], ev)
+	}
+
+	vars := make([]*Var, 0, len(keys))
+	for _, key := range keys {
+		decls := declared[key]
+		merged := *decls[len(decls)-1]
+		for _, ev := range decls[:len(decls)-1] {
+			merged.optional = merged.optional && ev.optional
+			merged.mustBeSet = merged.mustBeSet || ev.mustBeSet
+			merged.hasDefault = merged.hasDefault || ev.hasDefault
+			if ev.secret && (!merged.secret || ev.keepLast < merged.keepLast) {
+				merged.secret, merged.keepLast = true, ev.keepLast
+			}
+		}
+		vars = append(vars, &merged)
+	}
+	return vars
+}
+
+// A variable that must be set, as reported by Genv.Required.
+type RequiredVar struct {
+	Key string
+	// Whether a default was declared, which may stand in for the variable
+	// when defaults are allowed.
+	HasDefault bool
+}
+
+// Returns the variables declared so far, including those declared on
+// groups, that are not optional, in declaration order. This suits
+// generating a .env.example file or a startup banner.
+func (genv *Genv) Required() []RequiredVar {
+	var required []RequiredVar
+	for _, ev := range genv.registry.all() {
+		if !ev.skipped && (!ev.optional || ev.mustBeSet) {
+			required = append(required, RequiredVar{Key: ev.key, HasDefault: ev.hasDefault})
+		}
+	}
+	return required
+}
+
+// Returns where the value of each variable declared so far came from,
+// including those declared on groups, keyed by their full keys.
+func (genv *Genv) Provenance() map[string]Origin {
+	vars := genv.registry.all()
+	provenance := make(map[string]Origin, len(vars))
+	for _, ev := range vars {
+		provenance[ev.key] = ev.origin
+	}
+	return provenance
+}
+
+// Returns the resolved value of every variable declared so far, including
+// those declared on groups, keyed by their full keys. Values of variables
+// marked with Secret or RedactKeeping are masked, so the result is safe to
+// log.
+func (genv *Genv) Redacted() map[string]string {
+	vars := genv.registry.all()
+	redacted := make(map[string]string, len(vars))
+	for _, ev := range vars {
+		redacted[ev.key] = ev.display()
+	}
+	return redacted
+}
+
+// Returns the variables declared so far as KEY=value lines in declaration
+// order, masked as by Redacted.
+func (genv *Genv) String() string {
+	var b strings.Builder
+	for _, ev := range genv.registry.all() {
+		fmt.Fprintf(&b, "%s=%s\n", ev.key, ev.display())
+	}
+	return b.String()
+}
+
+// Logs the resolved value of every variable declared so far, including
+// those declared on groups, as the attributes of a single event. Values of
+// variables marked with Secret are masked. Call it once all variables have
+// been declared and parsed.
+func (genv *Genv) LogConfig(logger *slog.Logger, level slog.Level) {
+	vars := genv.registry.all()
+	attrs := make([]slog.Attr, 0, len(vars)+1)
+	for _, ev := range vars {
+		attrs = append(attrs, slog.String(ev.key, ev.display()))
+	}
+	if genv.name != "" {
+		attrs = append(attrs, slog.String("genv", genv.name))
+	}
+	logger.LogAttrs(context.Background(), level, "resolved config", attrs...)
+}
+
+// Logs a message through the configured logger, if any.
+func (genv *Genv) log(level slog.Level, msg string, args ...any) {
+	if genv == nil || genv.logger == nil {
+		return
+	}
+	if genv.name != "" {
+		args = append(args, "genv", genv.name)
+	}
+	genv.logger.Log(context.Background(), level, msg, args...)
+}
+
+const errFmtInvalidVar = "%s is invalid: %w"
+
+// An error wrapped with the key of a variable by wrapErr.
+type varError struct {
+	msg string
+	err error
+}
+
+func (e *varError) Error() string {
+	return e.msg
+}
+
+func (e *varError) Unwrap() error {
+	return e.err
+}
+
+// Wraps err with the key of the environment variable, prefixed by the
+// name of the owning instance when one was given.
+func (ev *Var) wrapErr(err error) error {
+	wrapped := ev.genv.wrapErr(fmt.Errorf(errFmtInvalidVar, ev.key, err))
+	return &varError{msg: wrapped.Error(), err: err}
+}
+
+// Prefixes err with the name of the instance, if it has one, for errors
+// that concern the instance rather than a single variable.
+func (genv *Genv) wrapErr(err error) error {
+	if genv == nil || genv.name == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", genv.name, err)
+}
+
+// Strips the key that wrapErr added to err, if any, so that the failure of
+// an element can be reported under the key of its list instead. Any other
+// wrapping, such as context added by a caller's parser, is kept.
+func unwrapVarErr(err error) error {
+	if ve, ok := err.(*varError); ok {
+		return ve.err
+	}
+	return err
+}
+
+func parse[T any](ev *Var, fn func(string) (T, error)) (T, error) {
+
+	var result T
+	var err error
+
+	if ev.skipped {
+		return result, nil
+	}
+
+	if ev.err != nil {
+		return result, ev.wrapErr(ev.err)
+	}
+
+	if len(ev.validators) > 0 {
+		if err := checkValidators(ev.validators, reflect.TypeFor[T]()); err != nil {
+			return result, ev.wrapErr(err)
+		}
+	}
+
+	if !ev.optional && !ev.mustBeSet && ev.value == "" {
+		return result, ev.wrapErr(ErrRequiredEnvironmentVariable)
+	}
+
+	if ev.value == "" {
+		// If validation succeeded, then the value being empty means it was
+		// optional (or just an empty string is the desired output).
+		// In that case, use the zero value.
+		return result, nil
+	}
+
+	result, err = fn(ev.value)
+	if err == nil && len(ev.validators) > 0 {
+		result, err = validate(ev.validators, result)
+	}
+	if err != nil {
+		return result, ev.wrapErr(err)
+	}
+	return result, nil
+}
+
+// Runs validators, which must all be for values of type T, on result.
+// Callers check for validators first, since boxing result allocates.
+func validate[T any](validators []validator, result T) (T, error) {
+	var value any = result
+	for _, v := range validators {
+		var err error
+		if value, err = v.fn(value); err != nil {
+			return result, err
+		}
+	}
+	return value.(T), nil
+}
+
+func mustParse[T any](ev *Var, fn func(*Var) (T, error)) T {
+	result, err := fn(ev)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+var (
+	ErrRequiredEnvironmentVariable = errors.New("environment variable is empty or unset")
+	ErrReservedKey                 = errors.New("environment variable is reserved")
+	ErrUnsetEnvironmentVariable    = errors.New("environment variable is unset")
+)
+
+func parseMany[T any](ev *Var, fn func(*Var) (T, error), opts ...manyOpt) ([]T, error) {
+	for _, opt := range opts {
+		opt(ev)
+	}
+
+	if ev.skipped {
+		return nil, nil
+	}
+
+	if ev.err != nil {
+		return nil, ev.wrapErr(ev.err)
+	}
+
+	// Validators for elements run as each is parsed, and those for the
+	// list once all have been. Any others are left to fn, which may parse
+	// elements as another type before converting them.
+	var elemValidators, listValidators, rest []validator
+	if len(ev.validators) > 0 {
+		elemValidators, rest = validatorsOf(ev.validators, reflect.TypeFor[T]())
+		listValidators, rest = validatorsOf(rest, reflect.TypeFor[[]T]())
+	}
+
+	if ev.splitKey == "" {
+		return nil, ev.wrapErr(errors.New("split key cannot be empty"))
+	}
+	if ev.decimalComma && ev.splitKey == "," {
+		return nil, ev.wrapErr(errors.New("split key cannot be \",\" when using a decimal comma"))
+	}
+
+	split, err := ev.split()
+	if err != nil {
+		return nil, ev.wrapErr(err)
+	}
+	if ev.trimSpace {
+		for i, val := range split {
+			split[i] = strings.TrimSpace(val)
+		}
+	}
+	if ev.elemDefaults != nil {
+		if split, err = ev.fillElemDefaults(split); err != nil {
+			return nil, ev.wrapErr(err)
+		}
+	}
+
+	// Count the elements up front so that the result is allocated once and
+	// the checks below happen before anything is parsed.
+	count := 0
+	for _, val := range split {
+		if val != "" {
+			count++
+		}
+	}
+	if count == 0 && len(ev.defaultElems) > 0 && ev.defaultsAllowed() {
+		split = ev.defaultElems
+		ev.origin = OriginDefault
+		for _, val := range split {
+			if val != "" {
+				count++
+			}
+		}
+	}
+	if !ev.optional && !ev.mustBeSet && count == 0 {
+		return nil, ev.wrapErr(ErrRequiredEnvironmentVariable)
+	}
+
+	result := make([]T, 0, count)
+	elem := *ev
+	elem.validators = rest
+	for i, val := range split {
+		if val == "" {
+			continue
+		}
+		elem.value = val
+		parsed, err := fn(&elem)
+		if err != nil {
+			// Report the element's own failure under the key once,
+			// rather than nesting one "is invalid" inside another.
+			return nil, ev.wrapErr(fmt.Errorf("element %d: %w", i, unwrapVarErr(err)))
+		}
+		if len(elemValidators) > 0 {
+			if parsed, err = validate(elemValidators, parsed); err != nil {
+				return nil, ev.wrapErr(fmt.Errorf("element %d: %w", i, err))
+			}
+		}
+		result = append(result, parsed)
+	}
+	if ev.unique {
+		if result, err = uniqueMany(result); err != nil {
+			return nil, ev.wrapErr(err)
+		}
+	}
+	if ev.sorted {
+		if err := sortMany(result); err != nil {
+			return nil, ev.wrapErr(err)
+		}
+	}
+	if len(listValidators) > 0 {
+		if result, err = validate(listValidators, result); err != nil {
+			return nil, ev.wrapErr(err)
+		}
+	}
+	return result, nil
+}
+
+// Reports whether defaults without their own allow option may be used,
+// which strict instances never permit.
+func (ev *Var) defaultsAllowed() bool {
+	if ev.genv != nil && ev.genv.strict {
+		return false
+	}
+	return ev.allowDefault != nil && ev.allowDefault(ev.genv)
+}
+
+// Fills empty positions of split with the per-element defaults, when
+// defaults are allowed, extending split to as many positions as there are
+// defaults. Since elements are positional, an empty position that no default
+// fills is an error unless every position is empty.
+func (ev *Var) fillElemDefaults(split []string) ([]string, error) {
+	filled := make([]string, max(len(split), len(ev.elemDefaults)))
+	copy(filled, split)
+	allowed := ev.defaultsAllowed()
+
+	empty := -1
+	for i, val := range filled {
+		if val == "" && allowed && i < len(ev.elemDefaults) {
+			filled[i] = ev.elemDefaults[i]
+			if filled[i] != "" && ev.origin == OriginUnset {
+				ev.origin = OriginDefault
+			}
+		}
+		if filled[i] == "" && empty < 0 {
+			empty = i
+		}
+	}
+	if empty >= 0 && slices.ContainsFunc(filled, func(val string) bool { return val != "" }) {
+		return nil, fmt.Errorf("element %d is empty", empty)
+	}
+	return filled, nil
+}
+
+// Discards range errors when saturation is enabled, keeping the clamped
+// value that strconv reports alongside them.
+func saturate[T any](ev *Var, result T, err error) (T, error) {
+	if ev.saturating && errors.Is(err, strconv.ErrRange) {
+		return result, nil
+	}
+	return result, err
+}
+
+// Splits the value into its raw elements. Values with more elements than
+// the limit, if one was set, fail before they are split.
+func (ev *Var) split() ([]string, error) {
+	if ev.flexibleList && strings.HasPrefix(strings.TrimSpace(ev.value), "[") {
+		return splitJSON(ev.value, ev.maxElements)
+	}
+
+	delim, trim := ev.splitKey, false
+	if ev.autoDelimiter {
+		delim, trim = autoDelimiter(ev.value, ev.splitKey, ev.decimalComma)
+	}
+	if ev.maxElements > 0 {
+		if count := countElements(ev.value, delim); count > ev.maxElements {
+			return nil, fmt.Errorf("%d elements exceed the limit of %d", count, ev.maxElements)
+		}
+	}
+	if delim == "" {
+		return strings.Fields(ev.value), nil
+	}
+
+	split := strings.Split(ev.value, delim)
+	if trim {
+		for i, val := range split {
+			split[i] = strings.TrimSpace(val)
+		}
+	}
+	return split, nil
+}
+
+// Picks whichever candidate delimiter occurs most often in value, falling
+// back to splitKey when there is no clear winner. Whitespace, returned as "",
+// is only a delimiter when no other candidate occurs, since it commonly pads
+// them; elements split on the others should be trimmed. A decimal comma
+// rules out ",", which then separates the parts of each number instead.
+func autoDelimiter(value, splitKey string, decimalComma bool) (delim string, trim bool) {
+	candidates := []string{",", ";", "|"}
+	if decimalComma {
+		candidates = candidates[1:]
+	}
+
+	var bestCount, ties int
+	for _, candidate := range candidates {
+		switch count := strings.Count(value, candidate); {
+		case count > bestCount:
+			delim, bestCount, ties = candidate, count, 0
+		case count > 0 && count == bestCount:
+			ties++
+		}
+	}
+
+	switch {
+	case ties > 0:
+		return splitKey, false
+	case bestCount == 0 && countElements(value, "") > 1:
+		return "", false
+	case bestCount == 0:
+		return splitKey, false
+	}
+	return delim, true
+}
+
+// Counts the elements that splitting value on delim yields, without
+// splitting it. An empty delim counts fields separated by whitespace.
+func countElements(value, delim string) int {
+	if delim != "" {
+		return strings.Count(value, delim) + 1
+	}
+	count, inField := 0, false
+	for _, r := range value {
+		if unicode.IsSpace(r) {
+			inField = false
+		} else if !inField {
+			count, inField = count+1, true
+		}
+	}
+	return count
+}
+
+// Splits a JSON array into its elements. Strings are unquoted, and any other
+// element is kept in its JSON form. Arrays with more than limit elements, if
+// limit is positive, fail as soon as the excess element is reached.
+func splitJSON(value string, limit int) ([]string, error) {
+	dec := json.NewDecoder(strings.NewReader(value))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var split []string
+	for dec.More() {
+		if limit > 0 && len(split) == limit {
+			return nil, fmt.Errorf("elements exceed the limit of %d", limit)
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		var elem string
+		if err := json.Unmarshal(raw, &elem); err != nil {
+			elem = string(raw)
+		}
+		split = append(split, elem)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON array")
+	}
+	return split, nil
+}
+
+// Sorts result in the natural order of its underlying type, so that named
+// types, such as a port type based on int, sort like the type they are
+// based on.
+func sortMany[T any](result []T) error {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.String:
+		sortBy(result, reflect.Value.String)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sortBy(result, reflect.Value.Int)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sortBy(result, reflect.Value.Uint)
+	case reflect.Float32, reflect.Float64:
+		sortBy(result, reflect.Value.Float)
+	default:
+		return fmt.Errorf("sorting %s is not supported", reflect.TypeFor[[]T]())
+	}
+	return nil
+}
+
+// Sorts elems by the ordered key that key extracts from each. Keys are
+// extracted once up front rather than on every comparison.
+func sortBy[T any, K cmp.Ordered](elems []T, key func(reflect.Value) K) {
+	type keyed struct {
+		key  K
+		elem T
+	}
+	sorted := make([]keyed, len(elems))
+	for i, elem := range elems {
+		sorted[i] = keyed{key(reflect.ValueOf(elem)), elem}
+	}
+	slices.SortStableFunc(sorted, func(a, b keyed) int {
+		return cmp.Compare(a.key, b.key)
+	})
+	for i, k := range sorted {
+		elems[i] = k.elem
+	}
+}
+
+// Removes repeated elements, keeping the first of each in place.
+func uniqueMany[T any](result []T) (unique []T, err error) {
+	typ := reflect.TypeFor[T]()
+	if !typ.Comparable() {
+		return nil, fmt.Errorf("deduplicating %s is not supported", typ)
+	}
+	// Interface types are comparable, but hashing one that holds a value
+	// that is not, such as a slice, panics.
+	defer func() {
+		if r := recover(); r != nil {
+			rerr, ok := r.(runtime.Error)
+			if !ok {
+				panic(r)
+			}
+			unique, err = nil, fmt.Errorf("deduplicating %s: %w", typ, rerr)
+		}
+	}()
+
+	seen := make(map[any]struct{}, len(result))
+	unique = result[:0]
+	for _, elem := range result {
+		if _, ok := seen[elem]; !ok {
+			seen[elem] = struct{}{}
+			unique = append(unique, elem)
+		}
+	}
+	return unique, nil
 }
 
 func mustParseMany[T any](ev *Var, parse func(*Var) (T, error), opts ...manyOpt) []T {
@@ -1,8 +1,26 @@
 package genv
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"math"
+	"math/big"
+	"net"
 	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -26,16 +44,434 @@ func TestWithAllowDefault(t *testing.T) {
 	assert.True(t, genv.allowDefault(genv))
 }
 
+func TestWithStrictDefaults(t *testing.T) {
+	t.Setenv("GENV_ALLOW_DEFAULT", "true")
+	genv := New(WithStrictDefaults())
+
+	_, err := genv.Var("SECRET").Default("baked-in").TryString()
+	assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+
+	_, err = genv.Var("MIRRORS").TryManyString(genv.WithDefaultElements("a"))
+	assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+
+	assert.Equal(t, "explicit", genv.Var("SECRET").
+		Default("explicit", genv.WithAllowDefaultAlways()).
+		String())
+
+	t.Setenv("SECRET", "real")
+	assert.Equal(t, "real", genv.Var("SECRET").Default("baked-in").String())
+
+	t.Run("OverridesAllowDefault", func(t *testing.T) {
+		always := func(*Genv) bool { return true }
+		genv := New(WithStrictDefaults(), WithAllowDefault(always))
+
+		_, err := genv.Var("TOKEN").Default("baked-in").TryString()
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+
+		_, err = genv.Var("MIRRORS").TryManyString(genv.WithDefaultElements("a"))
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+
+		_, err = genv.Var("ADDR").TryManyString(genv.WithDefaultsPerElement("localhost", "80"))
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+	})
+}
+
+func TestWithName(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	db := New(WithName("db"), WithLogger(logger))
+	cache := New(WithName("cache"), WithLogger(logger))
+
+	dbVar := &Var{key: "TIMEOUT", value: "5m", genv: db}
+	cacheVar := &Var{key: "TIMEOUT", value: "5m", genv: cache}
+	dbVar.DurationClamp(time.Second, time.Minute)
+	cacheVar.DurationClamp(time.Second, time.Minute)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "genv=db")
+	assert.Contains(t, lines[1], "genv=cache")
+
+	_, err := (&Var{key: "PORT", genv: db}).TryInt()
+	assert.EqualError(t, err, "db: PORT is invalid: environment variable is empty or unset")
+	_, err = (&Var{key: "PORT", genv: New()}).TryInt()
+	assert.EqualError(t, err, "PORT is invalid: environment variable is empty or unset")
+
+	_, err = (&Var{key: "PORTS", value: "80", genv: db}).TryManyInt(db.WithSplitKey(""))
+	assert.EqualError(t, err, "db: PORTS is invalid: split key cannot be empty")
+}
+
+func TestLogConfig(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	t.Setenv("DB_PASSWORD", "hunter2")
+	genv := New(WithAllowDefault(func(*Genv) bool { return true }), WithName("api"))
+	_ = genv.Var("HOST").String()
+	_ = genv.Var("PORT").Default("8080").Int()
+	_ = genv.Group("DB").Var("PASSWORD").Secret().String()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	genv.LogConfig(logger, slog.LevelInfo)
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &event))
+	assert.Equal(t, "resolved config", event["msg"])
+	assert.Equal(t, "INFO", event["level"])
+	assert.Equal(t, "localhost", event["HOST"])
+	assert.Equal(t, "8080", event["PORT"])
+	assert.Equal(t, "****", event["DB_PASSWORD"])
+	assert.Equal(t, "api", event["genv"])
+	assert.NotContains(t, event, "GENV_ALLOW_DEFAULT")
+}
+
+func TestRequirePresent(t *testing.T) {
+	t.Setenv("EMPTY_VAR", "")
+	t.Setenv("SET_VAR", "value")
+	genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+
+	t.Run("PresentEmpty", func(t *testing.T) {
+		actual, err := genv.Var("EMPTY_VAR").RequirePresent().TryString()
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+
+		_, err = genv.Var("EMPTY_VAR").TryString()
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+	})
+
+	t.Run("PresentValue", func(t *testing.T) {
+		assert.Equal(t, "value", genv.Var("SET_VAR").RequirePresent().String())
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		_, err := genv.Var("UNSET_VAR").RequirePresent().TryString()
+		assert.EqualError(t, err, "UNSET_VAR is invalid: environment variable is unset")
+		assert.ErrorIs(t, err, ErrUnsetEnvironmentVariable)
+
+		_, err = genv.Var("UNSET_VAR").Default("fallback").RequirePresent().TryString()
+		assert.ErrorIs(t, err, ErrUnsetEnvironmentVariable)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		genv := New()
+		genv.Var("EMPTY_VAR").RequirePresent()
+		genv.Var("DEBUG").Optional()
+		assert.Equal(t, []RequiredVar{{Key: "EMPTY_VAR"}}, genv.Required())
+	})
+}
+
+func TestOnlyIf(t *testing.T) {
+	t.Setenv("REDIS_URL", "redis://cache:6379")
+
+	t.Run("Matching", func(t *testing.T) {
+		t.Setenv("CACHE_BACKEND", "redis")
+		ev := New().Var("REDIS_URL").OnlyIf("CACHE_BACKEND", "redis")
+		assert.Equal(t, "redis://cache:6379", ev.URL().String())
+	})
+
+	t.Run("OtherValue", func(t *testing.T) {
+		t.Setenv("CACHE_BACKEND", "memory")
+		ev := New().Var("REDIS_URL").OnlyIf("CACHE_BACKEND", "redis")
+		actual, err := ev.TryURL()
+		require.NoError(t, err)
+		assert.Nil(t, actual)
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		ev := New().Var("MISSING_URL").OnlyIf("CACHE_BACKEND", "redis")
+		assert.NotPanics(t, func() { ev.URL() })
+	})
+
+	t.Run("Skipped", func(t *testing.T) {
+		t.Setenv("CACHE_BACKEND", "memory")
+		genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+
+		for name, ev := range map[string]*Var{
+			"DefaultAfter":         genv.Var("MISSING_URL").OnlyIf("CACHE_BACKEND", "redis").Default("redis://x"),
+			"DefaultBefore":        genv.Var("MISSING_URL").Default("redis://x").OnlyIf("CACHE_BACKEND", "redis"),
+			"RequirePresentAfter":  genv.Var("MISSING_URL").OnlyIf("CACHE_BACKEND", "redis").RequirePresent(),
+			"RequirePresentBefore": genv.Var("MISSING_URL").RequirePresent().OnlyIf("CACHE_BACKEND", "redis"),
+		} {
+			t.Run(name, func(t *testing.T) {
+				actual, err := ev.TryString()
+				require.NoError(t, err)
+				assert.Empty(t, actual)
+
+				list, err := ev.TryManyString()
+				require.NoError(t, err)
+				assert.Empty(t, list)
+			})
+		}
+		assert.Empty(t, genv.Required())
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		t.Setenv("APP_CACHE_BACKEND", "redis")
+		t.Setenv("APP_REDIS_URL", "redis://app:6379")
+		ev := New().Group("APP").Var("REDIS_URL").OnlyIf("CACHE_BACKEND", "redis")
+		assert.Equal(t, "redis://app:6379", ev.String())
+	})
+}
+
+func TestRequired(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	genv := New()
+	genv.Var("HOST")
+	genv.Var("PORT").Default("8080")
+	genv.Var("DEBUG").Optional()
+	genv.Group("DB").Var("URL")
+
+	assert.Equal(t, []RequiredVar{
+		{Key: "HOST"},
+		{Key: "PORT", HasDefault: true},
+		{Key: "DB_URL"},
+	}, genv.Required())
+}
+
+func TestRedacted(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	t.Setenv("API_KEY", "s3cret")
+	t.Setenv("ACCOUNT", "9876501234")
+	genv := New()
+	genv.Var("HOST")
+	genv.Var("API_KEY").Secret()
+	genv.Var("ACCOUNT").RedactKeeping(4)
+
+	assert.Equal(t, map[string]string{
+		"HOST":    "localhost",
+		"API_KEY": "****",
+		"ACCOUNT": "****1234",
+	}, genv.Redacted())
+	assert.Equal(t, "HOST=localhost\nAPI_KEY=****\nACCOUNT=****1234\n", genv.String())
+	assert.NotContains(t, fmt.Sprint(genv), "s3cret")
+
+	t.Run("Redeclared", func(t *testing.T) {
+		t.Setenv("TOKEN", "hunter2")
+		t.Setenv("OTHER", "x")
+		genv := New()
+		_ = genv.Var("TOKEN").Secret().String()
+		_ = genv.Var("TOKEN").Optional().String()
+		assert.True(t, genv.Present("TOKEN"))
+		require.NoError(t, genv.Unique("TOKEN", "OTHER"))
+
+		assert.Equal(t, map[string]string{"TOKEN": "****"}, genv.Redacted())
+		assert.Equal(t, []RequiredVar{{Key: "TOKEN"}}, genv.Required())
+	})
+}
+
+func TestRedactKeeping(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		keep     int
+		expected string
+	}{
+		"account": {"9876501234", 4, "****1234"},
+		"short":   {"1234", 4, "****"},
+		"zero":    {"9876501234", 0, "****"},
+		"runes":   {"pässwörd", 3, "****örd"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := (&Var{key: "ACCOUNT", value: test.value}).RedactKeeping(test.keep)
+			assert.Equal(t, test.expected, ev.display())
+		})
+	}
+
+	t.Run("LogConfig", func(t *testing.T) {
+		t.Setenv("ACCOUNT", "9876501234")
+		genv := New()
+		assert.Equal(t, "9876501234", genv.Var("ACCOUNT").RedactKeeping(4).String())
+
+		var buf bytes.Buffer
+		genv.LogConfig(slog.New(slog.NewTextHandler(&buf, nil)), slog.LevelInfo)
+		assert.Contains(t, buf.String(), "ACCOUNT=****1234")
+		assert.NotContains(t, buf.String(), "98765")
+	})
+}
+
+func TestWithReservedKeys(t *testing.T) {
+	t.Setenv("LD_PRELOAD", "/tmp/evil.so")
+	t.Setenv("NEW_VAR", "new")
+	genv := New(WithReservedKeys("LD_PRELOAD", "NEW_VAR"))
+
+	t.Run("Declared", func(t *testing.T) {
+		ev := genv.Var("LD_PRELOAD")
+		assert.Empty(t, ev.value)
+		_, err := ev.Optional().TryArgs()
+		assert.ErrorIs(t, err, ErrReservedKey)
+		assert.ErrorContains(t, err, "LD_PRELOAD")
+	})
+
+	t.Run("Many", func(t *testing.T) {
+		_, err := genv.Var("LD_PRELOAD").TryManyInt()
+		assert.ErrorIs(t, err, ErrReservedKey)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		_, err := genv.Var("LD_PRELOAD").
+			Default("/tmp/other.so", genv.WithAllowDefaultAlways()).
+			TryArgs()
+		assert.ErrorIs(t, err, ErrReservedKey)
+	})
+
+	t.Run("LookedUp", func(t *testing.T) {
+		ev := genv.Var("OLD_VAR").DeprecatedFor("NEW_VAR")
+		assert.Empty(t, ev.value)
+		_, err := ev.Optional().TryString()
+		assert.ErrorIs(t, err, ErrReservedKey)
+		assert.ErrorContains(t, err, "NEW_VAR")
+
+		t.Setenv("OLD_VAR", "old")
+		_, err = genv.Var("OLD_VAR").DeprecatedFor("NEW_VAR").TryString()
+		assert.ErrorIs(t, err, ErrReservedKey)
+	})
+
+	t.Run("Present", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			assert.False(t, genv.Present("NEW_VAR"))
+		})
+	})
+
+	t.Run("AllowDefault", func(t *testing.T) {
+		t.Setenv("GENV_ALLOW_DEFAULT", "true")
+		genv := New(WithReservedKeys("GENV_ALLOW_DEFAULT"))
+		assert.NotPanics(t, func() {
+			_, err := genv.Var("UNSET_VAR").Default("fallback").TryString()
+			assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+		})
+	})
+
+	t.Run("NotReserved", func(t *testing.T) {
+		assert.Equal(t, "/tmp/evil.so", New().Var("LD_PRELOAD").String())
+	})
+}
+
+func TestWithDefaultSentinel(t *testing.T) {
+	t.Setenv("SENTINEL_VAR", "default")
+	t.Setenv("REAL_VAR", "value")
+
+	t.Run("Enabled", func(t *testing.T) {
+		genv := New(WithDefaultSentinel("default"), WithAllowDefault(func(*Genv) bool { return true }))
+		assert.Equal(t, "fallback", genv.Var("SENTINEL_VAR").Default("fallback").String())
+		assert.Equal(t, "value", genv.Var("REAL_VAR").Default("fallback").String())
+	})
+
+	t.Run("DefaultDisallowed", func(t *testing.T) {
+		genv := New(WithDefaultSentinel("default"))
+		_, err := genv.Var("SENTINEL_VAR").Default("fallback").TryString()
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		genv := newGenv()
+		assert.Equal(t, "default", genv.Var("SENTINEL_VAR").Default("fallback").String())
+	})
+}
+
+func TestWithEnvSnapshotFunc(t *testing.T) {
+	t.Setenv("SNAPSHOT_VAR", "real")
+	t.Setenv("REAL_ONLY_VAR", "real")
+
+	genv := New(WithEnvSnapshotFunc(func() []string {
+		return []string{
+			"SNAPSHOT_VAR=snapshot",
+			"EQUALS_VAR=a=b",
+			"EMPTY_VAR=",
+			"=C:=C:\\",
+			"MALFORMED",
+		}
+	}))
+
+	assert.Equal(t, "snapshot", genv.Var("SNAPSHOT_VAR").String())
+	assert.Equal(t, "a=b", genv.Var("EQUALS_VAR").String())
+	assert.True(t, genv.Var("EMPTY_VAR").found)
+	assert.Equal(t, `C:\`, genv.Var("=C:").String())
+	assert.False(t, genv.Var("MALFORMED").found)
+	assert.False(t, genv.Var("REAL_ONLY_VAR").found)
+}
+
+func TestWithSnapshot(t *testing.T) {
+	t.Setenv("SNAPSHOT_VAR", "before")
+	genv := New(WithSnapshot())
+	t.Setenv("SNAPSHOT_VAR", "after")
+	t.Setenv("LATER_VAR", "after")
+
+	assert.Equal(t, "before", genv.Var("SNAPSHOT_VAR").String())
+	assert.Equal(t, OriginEnv, genv.Var("SNAPSHOT_VAR").Origin())
+	assert.False(t, genv.Var("LATER_VAR").found)
+}
+
+func TestWithStdinSentinel(t *testing.T) {
+	t.Setenv("TOKEN", "-")
+	t.Setenv("OTHER_VAR", "value")
+
+	t.Run("Enabled", func(t *testing.T) {
+		genv := New(WithStdinSentinel())
+		genv.stdin = bytes.NewReader([]byte("  s3cret\n"))
+		assert.Equal(t, "s3cret", genv.Var("TOKEN").String())
+		assert.Equal(t, "value", genv.Var("OTHER_VAR").String())
+	})
+
+	t.Run("CheckedFirst", func(t *testing.T) {
+		genv := New(WithStdinSentinel())
+		genv.stdin = bytes.NewReader([]byte("s3cret"))
+		assert.True(t, genv.Present("TOKEN"))
+		require.NoError(t, genv.Unique("TOKEN", "OTHER_VAR"))
+		assert.Equal(t, "s3cret", genv.Var("TOKEN").String())
+	})
+
+	t.Run("ReadError", func(t *testing.T) {
+		genv := New(WithStdinSentinel())
+		genv.stdin = iotest.ErrReader(errors.New("boom"))
+		_, err := genv.Var("TOKEN").TryString()
+		assert.EqualError(t, err, "TOKEN is invalid: reading standard input: boom")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		assert.Equal(t, "-", New().Var("TOKEN").String())
+	})
+}
+
+func TestGroup(t *testing.T) {
+	t.Setenv("DB_HOST", "db.local")
+	t.Setenv("CACHE_HOST", "cache.local")
+	t.Setenv("DB_REPLICA_HOST", "replica.local")
+	t.Setenv("GENV_ALLOW_DEFAULT", "true")
+
+	genv := New(WithName("app"))
+	db := genv.Group("DB")
+	cache := genv.Group("CACHE")
+
+	assert.Equal(t, "db.local", db.Var("HOST").String())
+	assert.Equal(t, "cache.local", cache.Var("HOST").String())
+	assert.Equal(t, "replica.local", db.Group("REPLICA").Var("HOST").String())
+	assert.Equal(t, "app", db.name)
+	assert.Same(t, genv, db.Group("REPLICA").root())
+
+	t.Run("AllowDefaultFromRoot", func(t *testing.T) {
+		assert.Equal(t, "5432", db.Var("PORT").Default("5432").String())
+	})
+
+	t.Run("ErrorsUseFullKey", func(t *testing.T) {
+		_, err := cache.Var("PORT").TryInt()
+		assert.ErrorContains(t, err, "CACHE_PORT is invalid")
+	})
+
+	t.Run("DeprecatedFor", func(t *testing.T) {
+		t.Setenv("DB_ADDR", "addr.local")
+		assert.Equal(t, "addr.local", db.Var("HOSTNAME").DeprecatedFor("ADDR").String())
+	})
+}
+
 func TestNew(t *testing.T) {
 	for name, test := range map[string]struct {
-		value         string
-		opts          []envVarOpt
-		expectedValue string
-		expectedFound bool
+		value          string
+		opts           []envVarOpt
+		expectedValue  string
+		expectedFound  bool
+		expectedOrigin Origin
 	}{
-		"Defined":     {"val", nil, "val", true},
-		"Undefined":   {"", nil, "", false},
-		"WithOptions": {"val", []envVarOpt{func(e *Var) { e.value = "opts" }}, "opts", true},
+		"Defined":     {"val", nil, "val", true, OriginEnv},
+		"Undefined":   {"", nil, "", false, OriginUnset},
+		"WithOptions": {"val", []envVarOpt{func(e *Var) { e.value = "opts" }}, "opts", true, OriginEnv},
 	} {
 		t.Run(name, func(t *testing.T) {
 			const key = "TEST_VAR"
@@ -50,6 +486,7 @@ func TestNew(t *testing.T) {
 				found:    test.expectedFound,
 				splitKey: ",",
 				genv:     genv,
+				origin:   test.expectedOrigin,
 			}
 			// We cannot test function equality
 			expected.allowDefault, actual.allowDefault = nil, nil
@@ -80,122 +517,1751 @@ func TestWithSplitKey(t *testing.T) {
 	assert.Equal(t, []int{123, 456}, actual)
 }
 
+func TestWithMaxElements(t *testing.T) {
+	genv := New()
+
+	t.Run("AtLimit", func(t *testing.T) {
+		ev := genv.Var("TEST_VAR", func(v *Var) { v.value = "1,2,3" })
+		actual, err := ev.TryManyInt(genv.WithMaxElements(3))
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, actual)
+	})
+
+	t.Run("OverLimit", func(t *testing.T) {
+		ev := genv.Var("TEST_VAR", func(v *Var) { v.value = "1,2,3,4" })
+		_, err := ev.TryManyInt(genv.WithMaxElements(3))
+		assert.EqualError(t, err, "TEST_VAR is invalid: 4 elements exceed the limit of 3")
+	})
+
+	t.Run("BeforeParsing", func(t *testing.T) {
+		ev := genv.Var("TEST_VAR", func(v *Var) { v.value = "1,2,invalid,4" })
+		_, err := ev.TryManyInt(genv.WithMaxElements(3))
+		assert.ErrorContains(t, err, "exceed the limit of 3")
+	})
+
+	t.Run("EmptyElements", func(t *testing.T) {
+		ev := genv.Var("TEST_VAR", func(v *Var) { v.value = "1,,,,2" })
+		_, err := ev.TryManyInt(genv.WithMaxElements(3))
+		assert.EqualError(t, err, "TEST_VAR is invalid: 5 elements exceed the limit of 3")
+	})
+
+	t.Run("AutoDelimiter", func(t *testing.T) {
+		ev := genv.Var("TEST_VAR", func(v *Var) { v.value = "a b c d" })
+		_, err := ev.TryManyString(genv.WithAutoDelimiter(), genv.WithMaxElements(3))
+		assert.EqualError(t, err, "TEST_VAR is invalid: 4 elements exceed the limit of 3")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		ev := genv.Var("TEST_VAR", func(v *Var) { v.value = `[1, 2, 3, 4, "unterminated` })
+		_, err := ev.TryManyInt(genv.WithFlexibleList(), genv.WithMaxElements(3))
+		assert.EqualError(t, err, "TEST_VAR is invalid: elements exceed the limit of 3")
+	})
+}
+
+func TestWithDefaultElements(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+		ev := genv.Var("MIRRORS", func(v *Var) { v.value = "" })
+		actual, err := ev.TryManyString(genv.WithDefaultElements("a.example.com,b"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.example.com,b"}, actual)
+		assert.Equal(t, OriginDefault, ev.Origin())
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+		ev := genv.Var("MIRRORS", func(v *Var) { v.value = "c,d" })
+		actual, err := ev.TryManyString(genv.WithDefaultElements("a", "b"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c", "d"}, actual)
+	})
+
+	t.Run("DefaultsNotAllowed", func(t *testing.T) {
+		genv := New(WithAllowDefault(func(*Genv) bool { return false }))
+		ev := genv.Var("MIRRORS", func(v *Var) { v.value = "" })
+		_, err := ev.TryManyString(genv.WithDefaultElements("a"))
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+	})
+}
+
+func TestWithTrimSpace(t *testing.T) {
+	genv := New()
+
+	t.Run("Trimmed", func(t *testing.T) {
+		ev := genv.Var("TAGS", func(v *Var) { v.value = " a, b ,\tc " })
+		assert.Equal(t, []string{"a", "b", "c"}, ev.ManyString(genv.WithTrimSpace()))
+	})
+
+	t.Run("StrictParser", func(t *testing.T) {
+		ev := genv.Var("PORTS", func(v *Var) { v.value = "80, 443" })
+		_, err := ev.TryManyInt()
+		assert.Error(t, err)
+		assert.Equal(t, []int{80, 443}, ev.ManyInt(genv.WithTrimSpace()))
+	})
+
+	t.Run("BlankElements", func(t *testing.T) {
+		ev := genv.Var("TAGS", func(v *Var) { v.value = "a,  ,b" })
+		assert.Equal(t, []string{"a", "b"}, ev.ManyString(genv.WithTrimSpace()))
+	})
+
+	t.Run("AllBlank", func(t *testing.T) {
+		ev := genv.Var("TAGS", func(v *Var) { v.value = " , " })
+		_, err := ev.TryManyString(genv.WithTrimSpace())
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+	})
+}
+
+func TestWithDefaultsPerElement(t *testing.T) {
+	genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+	defaults := genv.WithDefaultsPerElement("10", "20", "30")
+
+	for name, test := range map[string]struct {
+		value    string
+		expected []int
+		err      string
+	}{
+		"filled":   {",50,", []int{10, 50, 30}, ""},
+		"complete": {"1,2,3", []int{1, 2, 3}, ""},
+		"short":    {"5", []int{5, 20, 30}, ""},
+		"empty":    {"", []int{10, 20, 30}, ""},
+		"extra":    {"1,,3,4", []int{1, 20, 3, 4}, ""},
+		"noDefault": {"1,2,3,,5", nil,
+			"THRESHOLDS is invalid: element 3 is empty"},
+		"invalid": {",x,", nil,
+			`THRESHOLDS is invalid: element 1: strconv.Atoi: parsing "x": invalid syntax`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := genv.Var("THRESHOLDS", func(v *Var) { v.value = test.value })
+			actual, err := ev.TryManyInt(defaults)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	t.Run("Origin", func(t *testing.T) {
+		ev := genv.Var("THRESHOLDS")
+		_, err := ev.TryManyInt(defaults)
+		require.NoError(t, err)
+		assert.Equal(t, OriginDefault, ev.Origin())
+	})
+
+	t.Run("DefaultsNotAllowed", func(t *testing.T) {
+		genv := New(WithAllowDefault(func(*Genv) bool { return false }))
+		ev := genv.Var("THRESHOLDS", func(v *Var) { v.value = ",50," })
+		_, err := ev.TryManyInt(genv.WithDefaultsPerElement("10", "20", "30"))
+		assert.EqualError(t, err, "THRESHOLDS is invalid: element 0 is empty")
+
+		ev = genv.Var("THRESHOLDS").Optional()
+		actual, err := ev.TryManyInt(genv.WithDefaultsPerElement("10", "20", "30"))
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+}
+
+func TestWithUnique(t *testing.T) {
+	genv := New()
+
+	t.Run("Strings", func(t *testing.T) {
+		ev := genv.Var("TAGS", func(v *Var) { v.value = "b,a,b,c,a" })
+		assert.Equal(t, []string{"b", "a", "c"}, ev.ManyString(genv.WithUnique()))
+	})
+
+	t.Run("CanonicalEnums", func(t *testing.T) {
+		type feature string
+		parseFeature := func(ev *Var) (feature, error) {
+			s, err := ev.TryString()
+			return feature(s), err
+		}
+
+		ev := genv.Var("FEATURES", func(v *Var) { v.value = "Auth,auth,LOGGING" })
+		ev = OneOf(ev, "Auth", "Logging").CaseFold()
+		features, err := TryMany(ev, parseFeature, genv.WithUnique())
+		require.NoError(t, err)
+		assert.Equal(t, []feature{"Auth", "Logging"}, features)
+	})
+
+	t.Run("Sorted", func(t *testing.T) {
+		ev := genv.Var("PORTS", func(v *Var) { v.value = "443,80,443" })
+		assert.Equal(t, []int{80, 443}, ev.ManyInt(genv.WithUnique(), genv.WithSorted()))
+	})
+
+	t.Run("NotComparable", func(t *testing.T) {
+		ev := genv.Var("GROUPS", func(v *Var) { v.value = "a,b" })
+		_, err := TryMany(ev, func(ev *Var) ([]string, error) {
+			return []string{ev.value}, nil
+		}, genv.WithUnique())
+		assert.EqualError(t, err, "GROUPS is invalid: deduplicating []string is not supported")
+		assert.Panics(t, func() {
+			Many(ev, func(ev *Var) ([]string, error) { return nil, nil }, genv.WithUnique())
+		})
+	})
+
+	t.Run("InterfaceNotComparable", func(t *testing.T) {
+		ev := genv.Var("GROUPS", func(v *Var) { v.value = "a,b" })
+		_, err := TryMany(ev, func(ev *Var) (any, error) {
+			return []string{ev.value}, nil
+		}, genv.WithUnique())
+		assert.ErrorContains(t, err, "GROUPS is invalid: deduplicating interface {}: ")
+		var rerr runtime.Error
+		assert.ErrorAs(t, err, &rerr)
+
+		actual, err := TryMany(ev, func(ev *Var) (any, error) {
+			return ev.value, nil
+		}, genv.WithUnique())
+		require.NoError(t, err)
+		assert.Equal(t, []any{"a", "b"}, actual)
+	})
+
+	t.Run("Pointers", func(t *testing.T) {
+		ev := genv.Var("URLS", func(v *Var) { v.value = "http://a,http://a" })
+		assert.Len(t, ev.ManyURL(genv.WithUnique()), 2)
+	})
+}
+
+func TestWithAutoDelimiter(t *testing.T) {
+	genv := New()
+
+	for name, test := range map[string]struct {
+		value    string
+		splitKey string
+		expected []string
+	}{
+		"semicolon":  {"a;b;c", ",", []string{"a", "b", "c"}},
+		"pipe":       {"a|b|c", ",", []string{"a", "b", "c"}},
+		"comma":      {"a,b;c,d", ";", []string{"a", "b;c", "d"}},
+		"whitespace": {" a  b\tc ", ",", []string{"a", "b", "c"}},
+		"padded":     {"a; b; c", ",", []string{"a", "b", "c"}},
+		"tie":        {"a;b|c", "|", []string{"a;b", "c"}},
+		"single":     {"a", ",", []string{"a"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, splitKey: test.splitKey}
+			assert.Equal(t, test.expected, ev.ManyString(genv.WithAutoDelimiter()))
+		})
+	}
+
+	t.Run("Typed", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "1;2;3", splitKey: ","}
+		assert.Equal(t, []int{1, 2, 3}, ev.ManyInt(genv.WithAutoDelimiter()))
+	})
+}
+
+func TestWithSorted(t *testing.T) {
+	genv := New()
+
+	t.Run("Int", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "30,-1,20,10", splitKey: ","}
+		assert.Equal(t, []int{-1, 10, 20, 30}, ev.ManyInt(genv.WithSorted()))
+	})
+
+	t.Run("String", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "b,c,a", splitKey: ","}
+		assert.Equal(t, []string{"a", "b", "c"}, ev.ManyString(genv.WithSorted()))
+	})
+
+	t.Run("Float64", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "1.5,0.5", splitKey: ","}
+		assert.Equal(t, []float64{0.5, 1.5}, ev.ManyFloat64(genv.WithSorted()))
+	})
+
+	t.Run("NamedType", func(t *testing.T) {
+		type port int
+		ev := &Var{key: "TEST_VAR", value: "443,80,8080", splitKey: ","}
+		actual, err := TryMany(ev, func(ev *Var) (port, error) {
+			p, err := ev.TryPort()
+			return port(p), err
+		}, genv.WithSorted())
+		require.NoError(t, err)
+		assert.Equal(t, []port{80, 443, 8080}, actual)
+	})
+
+	t.Run("Duration", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "1m,1s,1h", splitKey: ","}
+		assert.Equal(t, []time.Duration{time.Second, time.Minute, time.Hour},
+			ev.ManyDuration(genv.WithSorted()))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "true,false", splitKey: ","}
+		_, err := ev.TryManyBool(genv.WithSorted())
+		assert.ErrorContains(t, err, "sorting []bool is not supported")
+	})
+}
+
+func TestWithFlexibleList(t *testing.T) {
+	genv := New()
+
+	for name, test := range map[string]struct {
+		value    string
+		expected []string
+		err      bool
+	}{
+		"delimited":  {"a,b,c", []string{"a", "b", "c"}, false},
+		"json":       {`["a","b","c"]`, []string{"a", "b", "c"}, false},
+		"jsonSpaces": {` [ "a", "b,c" ] `, []string{"a", "b,c"}, false},
+		"jsonScalar": {`["a", 1, true]`, []string{"a", "1", "true"}, false},
+		"jsonEmpty":  {`["a", ""]`, []string{"a"}, false},
+		"malformed":  {`["a",`, nil, true},
+		"trailing":   {`["a"] x`, nil, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, splitKey: ","}
+			actual, err := ev.TryManyString(genv.WithFlexibleList())
+			if test.err {
+				assert.ErrorContains(t, err, "TEST_VAR is invalid")
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Typed", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "[1, 2, 3]", splitKey: ","}
+		assert.Equal(t, []int{1, 2, 3}, ev.ManyInt(genv.WithFlexibleList()))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: `["a","b"]`, splitKey: ","}
+		assert.Equal(t, []string{`["a"`, `"b"]`}, ev.ManyString())
+	})
+}
+
 type MockDefaultOpt struct {
 	mock.Mock
 }
 
-func (m *MockDefaultOpt) optFunc() {
-	_ = m.Called()
+func (m *MockDefaultOpt) optFunc() {
+	_ = m.Called()
+}
+
+func TestDefault(t *testing.T) {
+	allow := func(*Genv) bool { return true }
+	disallow := func(*Genv) bool { return false }
+	for name, test := range map[string]struct {
+		found         bool
+		opts          []func(*Genv) bool
+		expectedValue string
+	}{
+		"Found":              {true, nil, "val"},
+		"FoundAllowed":       {true, []func(*Genv) bool{allow}, "val"},
+		"FoundDisallowed":    {true, []func(*Genv) bool{disallow}, "val"},
+		"NotFound":           {false, nil, "default"},
+		"NotFoundAllowed":    {false, []func(*Genv) bool{allow}, "default"},
+		"NotFoundDisallowed": {false, []func(*Genv) bool{disallow}, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+
+			if test.found {
+				t.Setenv("TEST_VAR", "val")
+			}
+
+			customOpt := new(MockDefaultOpt)
+			customOpt.On("optFunc")
+			opts := []defaultOpt{func(fb *fallback) { customOpt.optFunc() }}
+			fallbackOpts := make([]defaultOpt, len(test.opts))
+			for i, opt := range test.opts {
+				fallbackOpts[i] = genv.WithAllowDefault(opt)
+			}
+			opts = append(opts, fallbackOpts...)
+			actual := (*Var).Default(
+				genv.Var("TEST_VAR"),
+				"default",
+				opts...,
+			)
+			assert.Equal(t, test.expectedValue, actual.value)
+			customOpt.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeprecatedFor(t *testing.T) {
+	for name, test := range map[string]struct {
+		oldValue *string
+		newValue *string
+		expected string
+		key      string
+		logged   string
+	}{
+		"OldOnly": {ptr("old"), nil, "old", "OLD_VAR", "set its replacement instead"},
+		"NewOnly": {nil, ptr("new"), "new", "NEW_VAR", ""},
+		"Both":    {ptr("old"), ptr("new"), "new", "NEW_VAR", "ignored in favor of its replacement"},
+		"Neither": {nil, nil, "", "NEW_VAR", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if test.oldValue != nil {
+				t.Setenv("OLD_VAR", *test.oldValue)
+			}
+			if test.newValue != nil {
+				t.Setenv("NEW_VAR", *test.newValue)
+			}
+			var buf bytes.Buffer
+			genv := New(WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+
+			ev := genv.Var("OLD_VAR").DeprecatedFor("NEW_VAR")
+			assert.Equal(t, test.key, ev.key)
+			assert.Equal(t, test.expected, ev.Optional().String())
+			if test.logged == "" {
+				assert.Empty(t, buf.String())
+			} else {
+				assert.Contains(t, buf.String(), test.logged)
+				assert.Contains(t, buf.String(), "key=OLD_VAR replacement=NEW_VAR")
+			}
+		})
+	}
+
+	t.Run("Registered", func(t *testing.T) {
+		t.Setenv("NEW_VAR", "new")
+		genv := New()
+		genv.Var("OLD_VAR").DeprecatedFor("NEW_VAR")
+		assert.Equal(t, map[string]Origin{"NEW_VAR": OriginEnv}, genv.Provenance())
+		assert.Equal(t, []RequiredVar{{Key: "NEW_VAR"}}, genv.Required())
+	})
+
+	t.Run("DefaultFirst", func(t *testing.T) {
+		genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+		ev := genv.Var("OLD_VAR").Default("fallback").DeprecatedFor("NEW_VAR")
+		assert.Equal(t, "fallback", ev.String())
+		assert.Equal(t, map[string]Origin{"NEW_VAR": OriginDefault}, genv.Provenance())
+	})
+}
+
+func TestDefaultFromEnv(t *testing.T) {
+	for name, test := range map[string]struct {
+		primary  *string
+		backup   *string
+		allow    bool
+		expected string
+		err      bool
+	}{
+		"PrimarySet":       {ptr("primary"), ptr("backup"), true, "primary", false},
+		"BackupUsed":       {nil, ptr("backup"), true, "backup", false},
+		"BothEmpty":        {nil, nil, true, "", true},
+		"BackupDisallowed": {nil, ptr("backup"), false, "", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if test.primary != nil {
+				t.Setenv("PRIMARY_VAR", *test.primary)
+			}
+			if test.backup != nil {
+				t.Setenv("BACKUP_VAR", *test.backup)
+			}
+			genv := New(WithAllowDefault(func(*Genv) bool { return test.allow }))
+			actual, err := genv.Var("PRIMARY_VAR").DefaultFromEnv("BACKUP_VAR").TryString()
+			if test.err {
+				assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+				assert.ErrorContains(t, err, "PRIMARY_VAR")
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("AllowOverride", func(t *testing.T) {
+		t.Setenv("BACKUP_VAR", "backup")
+		genv := New()
+		actual := genv.Var("PRIMARY_VAR").
+			DefaultFromEnv("BACKUP_VAR", genv.WithAllowDefaultAlways()).
+			String()
+		assert.Equal(t, "backup", actual)
+	})
+}
+
+func TestDefaultFunc(t *testing.T) {
+	t.Run("Used", func(t *testing.T) {
+		fixed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		genv := New(
+			WithClock(func() time.Time { return fixed }),
+			WithAllowDefault(func(*Genv) bool { return true }),
+		)
+		actual := genv.Var("START_AT").
+			DefaultFunc(func(genv *Genv) string {
+				return genv.Now().Add(time.Hour).Format(time.RFC3339)
+			}).
+			Time()
+		assert.Equal(t, fixed.Add(time.Hour), actual)
+	})
+
+	t.Run("NotCalledWhenFound", func(t *testing.T) {
+		t.Setenv("START_AT", "2024-01-01T00:00:00Z")
+		called := false
+		newGenv().Var("START_AT").DefaultFunc(func(*Genv) string {
+			called = true
+			return ""
+		})
+		assert.False(t, called)
+	})
+
+	t.Run("NotCalledWhenDisallowed", func(t *testing.T) {
+		called := false
+		New().Var("START_AT").DefaultFunc(func(*Genv) string {
+			called = true
+			return ""
+		})
+		assert.False(t, called)
+	})
+
+	t.Run("DefaultClock", func(t *testing.T) {
+		assert.WithinDuration(t, time.Now(), New().Now(), time.Second)
+	})
+}
+
+func TestEVarString(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		value    string
+		expected string
+		panics   bool
+	}{
+		{"Valid", "val", "val", false},
+		{"Invalid", "", "", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value}
+			if test.panics {
+				assert.Panics(t, func() { _ = ev.String() })
+			} else {
+				assert.Equal(t, test.expected, ev.String())
+			}
+		})
+	}
+}
+
+func TestEvarMatchGlob(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		pattern  string
+		expected string
+		err      bool
+	}{
+		"matching":    {"api.example.com", "*.example.com", "api.example.com", false},
+		"nonMatching": {"api.example.org", "*.example.com", "", true},
+		"badPattern":  {"api.example.com", "[", "", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.MatchGlob(test.pattern).TryString()
+			if test.err {
+				assert.ErrorContains(t, err, "TEST_VAR is invalid")
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		genv := newGenv()
+		assert.Equal(t, "www.example.com", genv.Var("TEST_VAR").
+			Default("www.example.com").
+			MatchGlob("*.example.com").
+			String())
+		assert.Panics(t, func() {
+			_ = genv.Var("TEST_VAR").Default("example.org").MatchGlob("*.example.com").String()
+		})
+	})
+
+	t.Run("Many", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "a.example.com,b.example.org", splitKey: ","}
+		_, err := ev.MatchGlob("*.example.com").TryManyString()
+		assert.ErrorContains(t, err, `"b.example.org" does not match`)
+	})
+}
+
+func TestEvarCharsetAllowed(t *testing.T) {
+	const allowed = "abcdefghijklmnopqrstuvwxyz0123456789-"
+	for name, test := range map[string]struct {
+		value string
+		err   string
+	}{
+		"allowed":    {"my-service-01", ""},
+		"disallowed": {"my_service", `TEST_VAR is invalid: "my_service" contains the disallowed character '_'`},
+		"multibyte":  {"café", `TEST_VAR is invalid: "café" contains the disallowed character 'é'`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.CharsetAllowed(allowed).TryString()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.value, actual)
+			}
+		})
+	}
+}
+
+func TestManyEvarString(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "val1,val2", splitKey: ","}
+		assert.Equal(t, []string{"val1", "val2"}, ev.ManyString())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: ""}
+		assert.Panics(t, func() { ev.ManyString() })
+	})
+
+	t.Run(("Empty"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
+		assert.Empty(t, ev.ManyString())
+	})
+}
+
+func TestTryManyEvarString(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected []string
+		err      bool
+	}{
+		"valid":    {"val1,val2", false, []string{"val1", "val2"}, false},
+		"empty":    {"", false, nil, true},
+		"optional": {"", true, []string{}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, optional: test.optional, splitKey: ","}
+			actual, err := ev.TryManyString()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("WrappedElement", func(t *testing.T) {
+		errUnknown := errors.New("unknown feature")
+		ev := &Var{key: "FEATURES", value: "auth,x", splitKey: ","}
+		_, err := TryMany(ev, func(ev *Var) (string, error) {
+			if ev.value != "auth" {
+				return "", fmt.Errorf("feature %q: %w", ev.value, errUnknown)
+			}
+			return ev.value, nil
+		})
+		assert.EqualError(t, err, `FEATURES is invalid: element 1: feature "x": unknown feature`)
+		assert.ErrorIs(t, err, errUnknown)
+	})
+}
+
+func TestEVarBool(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "true"}
+		assert.True(t, ev.Bool())
+		ev.value = "false"
+		assert.False(t, ev.Bool())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "invalid"}
+		assert.Panics(t, func() { ev.Bool() })
+	})
+}
+
+func TestEVarTryBool(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		value    string
+		expected bool
+		err      bool
+	}{
+		{"ValidTrue", "true", true, false},
+		{"ValidFalse", "false", false, false},
+		{"Missing", "", false, true},
+		{"Invalid", "invalid", false, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.TryBool()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestManyEvarBool(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "true,false", splitKey: ","}
+		assert.Equal(t, []bool{true, false}, ev.ManyBool())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "invalid"}
+		assert.Panics(t, func() { ev.ManyBool() })
+	})
+
+	t.Run(("Empty"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
+		assert.Empty(t, ev.ManyBool())
+	})
+}
+
+func TestWithStripPrefix(t *testing.T) {
+	t.Setenv("MYAPP_PORT", "8080")
+	t.Setenv("MYAPP_HOST", "prefixed")
+	t.Setenv("HOST", "plain")
+	t.Setenv("DEBUG", "true")
+	genv := New(WithStripPrefix("MYAPP_"))
+
+	assert.Equal(t, 8080, genv.Var("PORT").Int())
+	assert.Equal(t, "prefixed", genv.Var("HOST").String())
+	assert.True(t, genv.Var("DEBUG").Bool())
+	assert.False(t, genv.Var("MISSING").found)
+
+	t.Run("Group", func(t *testing.T) {
+		t.Setenv("MYAPP_DB_HOST", "db.local")
+		assert.Equal(t, "db.local", genv.Group("DB").Var("HOST").String())
+	})
+}
+
+func TestWithBoolTokens(t *testing.T) {
+	genv := New(WithBoolTokens([]string{"oui", "ja"}, []string{"non", "nein"}))
+
+	for name, test := range map[string]struct {
+		value    string
+		expected bool
+		err      string
+	}{
+		"truthy":   {"oui", true, ""},
+		"falsy":    {"nein", false, ""},
+		"case":     {"OUI", true, ""},
+		"standard": {"false", false, ""},
+		"unknown":  {"peut-être", false, `TEST_VAR is invalid: strconv.ParseBool: parsing "peut-être": invalid syntax`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := genv.Var("TEST_VAR", func(v *Var) { v.value = test.value })
+			actual, err := ev.TryBool()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	t.Run("Many", func(t *testing.T) {
+		ev := genv.Var("TEST_VAR", func(v *Var) { v.value = "ja,non" })
+		assert.Equal(t, []bool{true, false}, ev.ManyBool())
+	})
+}
+
+func TestBoolLoose(t *testing.T) {
+	for value, expected := range map[string]bool{
+		"yes": true, "Y": true, "on": true, "Enabled": true, "TRUE": true, "1": true,
+		"no": false, "n": false, "OFF": false, "disabled": false, "False": false, "0": false,
+	} {
+		t.Run(value, func(t *testing.T) {
+			ev := (&Var{key: "FEATURE", value: value}).BoolLoose()
+			actual, err := ev.TryBool()
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		ev := (&Var{key: "FEATURE", value: "maybe"}).BoolLoose()
+		_, err := ev.TryBool()
+		assert.Error(t, err)
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		ev := &Var{key: "FEATURE", value: "on"}
+		_, err := ev.TryBool()
+		assert.Error(t, err)
+	})
+}
+
+func TestTryManyEvarBool(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		value    string
+		optional bool
+		expected []bool
+		err      bool
+	}{
+		{"Valid", "true,false", false, []bool{true, false}, false},
+		{"Empty", "", false, []bool{}, true},
+		{"Optional", "", true, []bool{}, false},
+		{"Invalid", "invalid", false, []bool{}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, splitKey: ","}
+			if test.optional {
+				ev = ev.Optional()
+			}
+			actual, err := ev.TryManyBool()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvarInt(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "123"}
+		assert.Equal(t, 123, ev.Int())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "invalid"}
+		assert.Panics(t, func() { ev.Int() })
+	})
+}
+
+func TestEvarTryInt(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		value    string
+		optional bool
+		expected int
+		err      bool
+	}{
+		{"Valid", "123", false, 123, false},
+		{"Empty", "", false, 0, true},
+		{"Optional", "", true, 0, false},
+		{"Invalid", "invalid", false, 0, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value}
+			if test.optional {
+				ev = *ev.Optional()
+			}
+			actual, err := ev.TryInt()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvarSaturate(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected int
+		err      bool
+	}{
+		"aboveMax": {"99999999999999999999", math.MaxInt, false},
+		"belowMin": {"-99999999999999999999", math.MinInt, false},
+		"inRange":  {"123", 123, false},
+		"invalid":  {"invalid", 0, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.Saturate().TryInt()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "99999999999999999999"}
+		_, err := ev.TryInt()
+		assert.ErrorIs(t, err, strconv.ErrRange)
+	})
+
+	t.Run("Int32", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "2147483648"}
+		_, err := ev.TryInt32()
+		assert.ErrorIs(t, err, strconv.ErrRange)
+		assert.Equal(t, int32(math.MaxInt32), ev.Saturate().Int32())
+
+		ev = &Var{key: "TEST_VAR", value: "-2147483649"}
+		assert.Equal(t, int32(math.MinInt32), ev.Saturate().Int32())
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "-42"}
+		assert.Equal(t, uint(0), ev.Saturate().Uint())
+		assert.Equal(t, uint64(0), ev.Uint64())
+
+		ev = &Var{key: "TEST_VAR", value: "-99999999999999999999"}
+		assert.Equal(t, uint64(0), ev.Saturate().Uint64())
+
+		ev = &Var{key: "TEST_VAR", value: "-abc"}
+		_, err := ev.Saturate().TryUint()
+		assert.ErrorIs(t, err, strconv.ErrSyntax)
+	})
+}
+
+func TestEvarTryInt64(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected int64
+		err      string
+	}{
+		"large":    {"9000000000", 9000000000, ""},
+		"negative": {"-9000000000", -9000000000, ""},
+		"overflow": {"9223372036854775808", 0, `TEST_VAR is invalid: strconv.ParseInt: parsing "9223372036854775808": value out of range`},
+		"invalid":  {"1e3", 0, `TEST_VAR is invalid: strconv.ParseInt: parsing "1e3": invalid syntax`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.TryInt64()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	t.Run("Saturate", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "9223372036854775808"}
+		assert.Equal(t, int64(math.MaxInt64), ev.Saturate().Int64())
+	})
+
+	t.Run("Many", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "9000000000,1", splitKey: ","}
+		assert.Equal(t, []int64{9000000000, 1}, ev.ManyInt64())
+	})
+}
+
+func TestEvarTryUint(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected uint64
+		err      string
+	}{
+		"valid":    {"18446744073709551615", math.MaxUint64, ""},
+		"negative": {"-1", 0, `TEST_VAR is invalid: strconv.ParseUint: parsing "-1": invalid syntax`},
+		"overflow": {"18446744073709551616", 0, `TEST_VAR is invalid: strconv.ParseUint: parsing "18446744073709551616": value out of range`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.TryUint64()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	t.Run("Uint", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "42"}
+		assert.Equal(t, uint(42), ev.Uint())
+
+		ev = &Var{key: "TEST_VAR", value: "-42"}
+		assert.Panics(t, func() { ev.Uint() })
+	})
+
+	t.Run("Saturate", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "18446744073709551616"}
+		assert.Equal(t, uint64(math.MaxUint64), ev.Saturate().Uint64())
+		assert.Equal(t, uint(math.MaxUint), ev.Uint())
+	})
+
+	t.Run("Many", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "3,1,2", splitKey: ","}
+		assert.Equal(t, []uint{3, 1, 2}, ev.ManyUint())
+		ev = &Var{key: "TEST_VAR", value: "3,1,2", splitKey: ",", sorted: true}
+		assert.Equal(t, []uint64{1, 2, 3}, ev.ManyUint64())
+	})
+}
+
+func TestManyEvarInt(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "123,456", splitKey: ","}
+		assert.Equal(t, []int{123, 456}, ev.ManyInt())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "invalid", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyInt() })
+	})
+
+	t.Run(("Empty"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
+		assert.Empty(t, ev.ManyInt())
+	})
+}
+
+func TestTryManyEvarInt(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected []int
+		err      bool
+	}{
+		"valid":    {"123,456", false, []int{123, 456}, false},
+		"empty":    {"", false, []int{}, true},
+		"optional": {"", true, []int{}, false},
+		"invalid":  {"invalid", false, []int{}, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, splitKey: ","}
+			if test.optional {
+				ev = ev.Optional()
+			}
+			actual, err := ev.TryManyInt()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvarTryManyInt3(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected [3]int
+		err      bool
+	}{
+		"valid":    {"255,128,0", false, [3]int{255, 128, 0}, false},
+		"tooFew":   {"255,128", false, [3]int{}, true},
+		"tooMany":  {"255,128,0,1", false, [3]int{}, true},
+		"empty":    {"", false, [3]int{}, true},
+		"optional": {"", true, [3]int{}, false},
+		"invalid":  {"255,invalid,0", false, [3]int{}, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, optional: test.optional, splitKey: ","}
+			actual, err := ev.TryManyInt3()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "255,128", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyInt3() })
+	})
+}
+
+func TestEvarTryFloat32(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected float32
+		err      string
+	}{
+		"valid":    {"1.5", 1.5, ""},
+		"max":      {"3.4028234663852886e38", math.MaxFloat32, ""},
+		"tooLarge": {"1e39", 0, `TEST_VAR is invalid: strconv.ParseFloat: parsing "1e39": value out of range`},
+		"invalid":  {"invalid", 0, `TEST_VAR is invalid: strconv.ParseFloat: parsing "invalid": invalid syntax`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.TryFloat32()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				assert.Panics(t, func() { ev.Float32() })
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	t.Run("DecimalComma", func(t *testing.T) {
+		ev := (&Var{key: "TEST_VAR", value: "1,5;2,25", splitKey: ";"}).DecimalComma()
+		assert.Equal(t, []float32{1.5, 2.25}, ev.ManyFloat32())
+	})
+}
+
+func TestEvarTryPort(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected int
+		err      string
+	}{
+		"valid":       {"8080", 8080, ""},
+		"lowest":      {"1", 1, ""},
+		"highest":     {"65535", 65535, ""},
+		"zero":        {"0", 0, "TEST_VAR is invalid: port 0 is out of range [1, 65535]"},
+		"too large":   {"65536", 0, "TEST_VAR is invalid: port 65536 is out of range [1, 65535]"},
+		"non-numeric": {"http", 0, `TEST_VAR is invalid: strconv.Atoi: parsing "http": invalid syntax`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.TryPort()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestEvarTryManyPort(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "EXPOSE_PORTS", value: "80,443,8080", splitKey: ","}
+		assert.Equal(t, []int{80, 443, 8080}, ev.ManyPort())
+	})
+
+	t.Run("OutOfRange", func(t *testing.T) {
+		ev := &Var{key: "EXPOSE_PORTS", value: "80,70000,8080", splitKey: ","}
+		_, err := ev.TryManyPort()
+		assert.EqualError(t, err, "EXPOSE_PORTS is invalid: element 1: port 70000 is out of range [1, 65535]")
+		assert.Panics(t, func() { ev.ManyPort() })
+	})
+
+	t.Run("NonNumeric", func(t *testing.T) {
+		ev := &Var{key: "EXPOSE_PORTS", value: "80,https", splitKey: ","}
+		_, err := ev.TryManyPort()
+		assert.ErrorContains(t, err, "element 1: ")
+	})
+}
+
+func TestEvarFloat64(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "123.456"}
+		assert.Equal(t, 123.456, ev.Float64())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "invalid"}
+		assert.Panics(t, func() { ev.Float64() })
+	})
+}
+
+func TestEvarTryFloat64(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected float64
+		err      bool
+	}{
+		"valid":    {"123.456", false, 123.456, false},
+		"empty":    {"", false, 0.0, true},
+		"optional": {"", true, 0.0, false},
+		"invalid":  {"invalid", false, 0.0, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value}
+			if test.optional {
+				ev = *ev.Optional()
+			}
+			actual, err := ev.TryFloat64()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvarDecimalComma(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected float64
+		err      bool
+	}{
+		"comma":      {"1,5", 1.5, false},
+		"integer":    {"15", 15, false},
+		"negative":   {"-0,25", -0.25, false},
+		"period":     {"1.5", 0, true},
+		"thousands":  {"1,000,5", 0, true},
+		"mixedMarks": {"1.000,5", 0, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.DecimalComma().TryFloat64()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Many", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "1,5;2,25", splitKey: ";"}
+		assert.Equal(t, []float64{1.5, 2.25}, ev.DecimalComma().ManyFloat64())
+	})
+
+	t.Run("ConflictingSplitKey", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "1,5", splitKey: ","}
+		_, err := ev.DecimalComma().TryManyFloat64()
+		assert.ErrorContains(t, err, "TEST_VAR is invalid: split key cannot be")
+	})
+
+	t.Run("AutoDelimiter", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "1,5; 2,25", splitKey: ";"}
+		actual, err := ev.DecimalComma().TryManyFloat64(New().WithAutoDelimiter())
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1.5, 2.25}, actual)
+	})
+}
+
+func TestEvarManyFloat64(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "123.456,456.789", splitKey: ","}
+		assert.Equal(t, []float64{123.456, 456.789}, ev.ManyFloat64())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "invalid", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyFloat64() })
+	})
+
+	t.Run(("Empty"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
+		assert.Empty(t, ev.ManyFloat64())
+	})
+}
+
+func TestTryManyEvarFloat64(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		value    string
+		optional bool
+		expected []float64
+		err      bool
+	}{
+		{"Valid", "123.456,456.789", false, []float64{123.456, 456.789}, false},
+		{"Empty", "", false, []float64{}, true},
+		{"Optional", "", true, []float64{}, false},
+		{"Invalid", "invalid", false, []float64{}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, splitKey: ","}
+			if test.optional {
+				ev = ev.Optional()
+			}
+			actual, err := ev.TryManyFloat64()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvarTryBasisPoints(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		bounded  bool
+		expected float64
+		err      bool
+	}{
+		"valid":             {"150", false, false, 0.015, false},
+		"zero":              {"0", false, false, 0, false},
+		"max":               {"10000", false, true, 1, false},
+		"aboveRange":        {"10001", false, false, 1.0001, false},
+		"belowRange":        {"-1", false, false, -0.0001, false},
+		"aboveRangeBounded": {"10001", false, true, 0, true},
+		"belowRangeBounded": {"-1", false, true, 0, true},
+		"fractional":        {"1.5", false, false, 0, true},
+		"empty":             {"", false, false, 0, true},
+		"optional":          {"", true, true, 0, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "FEE", value: test.value, optional: test.optional, bpsBounded: test.bounded}
+			actual, err := ev.TryBasisPoints()
+			if test.err {
+				assert.ErrorContains(t, err, "FEE is invalid")
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := Var{key: "FEE", value: "10001"}
+		assert.Panics(t, func() { ev.BoundBasisPoints().BasisPoints() })
+	})
+}
+
+func TestEvarTryRat(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected *big.Rat
+		err      bool
+	}{
+		"ratio":           {"1/3", false, big.NewRat(1, 3), false},
+		"decimal":         {"0.5", false, big.NewRat(1, 2), false},
+		"integer":         {"2", false, big.NewRat(2, 1), false},
+		"zeroDenominator": {"1/0", false, nil, true},
+		"malformed":       {"one/three", false, nil, true},
+		"empty":           {"", false, nil, true},
+		"optional":        {"", true, nil, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "RATIO", value: test.value, optional: test.optional}
+			actual, err := ev.TryRat()
+			if test.err {
+				assert.ErrorContains(t, err, "RATIO is invalid")
+				return
+			}
+			assert.Nil(t, err)
+			if test.expected == nil {
+				assert.Nil(t, actual)
+			} else {
+				assert.Equal(t, 0, test.expected.Cmp(actual), "expected %s, got %s", test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := Var{key: "RATIO", value: "1/0"}
+		assert.Panics(t, func() { ev.Rat() })
+	})
+}
+
+func TestEvarTryFloat64Pair(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected Float64Pair
+		err      bool
+	}{
+		"valid":    {"37.77,-122.42", false, Float64Pair{37.77, -122.42}, false},
+		"tooFew":   {"37.77", false, Float64Pair{}, true},
+		"tooMany":  {"37.77,-122.42,10", false, Float64Pair{}, true},
+		"invalidA": {"north,-122.42", false, Float64Pair{}, true},
+		"invalidB": {"37.77,west", false, Float64Pair{}, true},
+		"empty":    {"", false, Float64Pair{}, true},
+		"optional": {"", true, Float64Pair{}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "GEO", value: test.value, optional: test.optional}
+			actual, err := ev.TryFloat64Pair(",")
+			if test.err {
+				assert.ErrorContains(t, err, "GEO is invalid")
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("DecimalComma", func(t *testing.T) {
+		ev := &Var{key: "GEO", value: "37,77;-122,42"}
+		assert.Equal(t, Float64Pair{37.77, -122.42}, ev.DecimalComma().Float64Pair(";"))
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := Var{key: "GEO", value: "37.77"}
+		assert.Panics(t, func() { ev.Float64Pair(",") })
+	})
+}
+
+func TestEvarURL(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "http://example.com:8080"}
+		url := ev.URL()
+		assert.Equal(t, "http", url.Scheme)
+		assert.Equal(t, "example.com", url.Hostname())
+		assert.Equal(t, "8080", url.Port())
+		assert.Equal(t, "http://example.com:8080", ev.URL().String())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "http://invalid url"}
+		assert.Panics(t, func() { ev.URL() })
+	})
+}
+
+func TestEvarTryURL(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		value    string
+		optional bool
+		expected string
+		err      bool
+	}{
+		{"Valid", "http://example.com:8080", false, "http://example.com:8080", false},
+		{"Empty", "", false, "", true},
+		{"Optional", "", true, "", false},
+		{"Invalid", "http://invalid url", false, "", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value}
+			if test.optional {
+				ev = *ev.Optional()
+			}
+			actual, err := ev.TryURL()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				if test.expected == "" {
+					assert.Nil(t, actual)
+				} else {
+					assert.Equal(t, test.expected, actual.String())
+				}
+			}
+		})
+	}
+}
+
+func TestEvarDefaultScheme(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected string
+		host     string
+		port     string
+	}{
+		"schemeless": {"example.com:8080", "https://example.com:8080", "example.com", "8080"},
+		"scheme":     {"http://example.com:8080", "http://example.com:8080", "example.com", "8080"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			actual, err := ev.DefaultScheme("https").TryURL()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual.String())
+			assert.Equal(t, test.host, actual.Hostname())
+			assert.Equal(t, test.port, actual.Port())
+		})
+	}
+
+	t.Run("Many", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "a.com,http://b.com", splitKey: ","}
+		urls := ev.DefaultScheme("https").ManyURL()
+		assert.Equal(t, "https://a.com", urls[0].String())
+		assert.Equal(t, "http://b.com", urls[1].String())
+	})
+}
+
+func TestEvarTryURLRedacted(t *testing.T) {
+	for name, test := range map[string]struct {
+		value string
+		full  string
+		safe  string
+	}{
+		"password": {"postgres://app:s3cret@db:5432/app", "postgres://app:s3cret@db:5432/app", "postgres://db:5432/app"},
+		"username": {"https://token@api.example.com/v1", "https://token@api.example.com/v1", "https://api.example.com/v1"},
+		"none":     {"https://example.com", "https://example.com", "https://example.com"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "DATABASE_URL", value: test.value}
+			full, safe, err := ev.TryURLRedacted()
+			require.NoError(t, err)
+			assert.Equal(t, test.full, full.String())
+			assert.Equal(t, test.safe, safe)
+		})
+	}
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "DATABASE_URL", optional: true}
+		full, safe := ev.URLRedacted()
+		assert.Nil(t, full)
+		assert.Empty(t, safe)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		ev := &Var{key: "DATABASE_URL", value: "http://invalid url"}
+		_, _, err := ev.TryURLRedacted()
+		assert.ErrorContains(t, err, "DATABASE_URL is invalid")
+		assert.Panics(t, func() { ev.URLRedacted() })
+	})
+}
+
+func TestRequireQueryParams(t *testing.T) {
+	for name, test := range map[string]struct {
+		value string
+		err   string
+	}{
+		"all":     {"https://hooks.example.com/x?token=abc&channel=ops", ""},
+		"empty":   {"https://hooks.example.com/x?token=&channel=ops", ""},
+		"missing": {"https://hooks.example.com/x?channel=ops", `WEBHOOK_URL is invalid: missing required query parameter "token"`},
+		"none":    {"https://hooks.example.com/x", `WEBHOOK_URL is invalid: missing required query parameter "token"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := (&Var{key: "WEBHOOK_URL", value: test.value}).RequireQueryParams("token", "channel")
+			actual, err := ev.TryURL()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.value, actual.String())
+		})
+	}
+}
+
+func TestManyEvarURL(t *testing.T) {
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "http://example.com:8080,http://example.com:8081", splitKey: ","}
+		urls := ev.ManyURL()
+		assert.Equal(t, "http://example.com:8080", urls[0].String())
+		assert.Equal(t, "http://example.com:8081", urls[1].String())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "http://invalid url", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyURL() })
+	})
+
+	t.Run(("Empty"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
+		assert.Empty(t, ev.ManyURL())
+	})
+}
+
+func TestTryManyEvarURL(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected []string
+		err      bool
+	}{
+		"valid": {
+			"http://example.com:8080,http://example.com:8081",
+			false,
+			[]string{
+				"http://example.com:8080",
+				"http://example.com:8081"},
+			false,
+		},
+		"empty":    {"", false, nil, true},
+		"optional": {"", true, nil, false},
+		"invalid":  {"http://invalid url", false, nil, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value, splitKey: ","}
+			if test.optional {
+				ev = *ev.Optional()
+			}
+			actual, err := ev.TryManyURL()
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.Nil(t, err)
+			if test.expected == nil {
+				assert.Equal(t, []*url.URL{}, actual)
+				return
+			}
+			for i, url := range actual {
+				assert.Equal(t, test.expected[i], url.String())
+			}
+		})
+	}
+
+	t.Run(("Valid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "http://example.com:8080,http://example.com:8081", splitKey: ","}
+		urls := ev.ManyURL()
+		assert.Equal(t, "http://example.com:8080", urls[0].String())
+		assert.Equal(t, "http://example.com:8081", urls[1].String())
+	})
+
+	t.Run(("Invalid"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "http://invalid url", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyURL() })
+	})
+
+	t.Run(("Empty"), func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
+		assert.Empty(t, ev.ManyURL())
+	})
+}
+
+func TestEvarTryBytes(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		encoding *base64.Encoding
+		optional bool
+		expected []byte
+		err      string
+	}{
+		"standard": {"aGk/Pz8=", nil, false, []byte("hi???"), ""},
+		"rawURL":   {"aGk_Pz8", base64.RawURLEncoding, false, []byte("hi???"), ""},
+		"optional": {"", nil, true, nil, ""},
+		"empty":    {"", nil, false, nil, "TEST_VAR is invalid: environment variable is empty or unset"},
+		"invalid":  {"aGk_Pz8", nil, false, nil, "TEST_VAR is invalid: illegal base64 data at input byte 3"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			if test.encoding != nil {
+				ev.Base64Encoding(test.encoding)
+			}
+			actual, err := ev.TryBytes()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				assert.Panics(t, func() { ev.Bytes() })
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestEvarTryHexBytes(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected []byte
+		err      string
+	}{
+		"lower":     {"deadbeef", false, []byte{0xde, 0xad, 0xbe, 0xef}, ""},
+		"upper":     {"DEADBEEF", false, []byte{0xde, 0xad, 0xbe, 0xef}, ""},
+		"optional":  {"", true, nil, ""},
+		"oddLength": {"abc", false, nil, "TEST_VAR is invalid: encoding/hex: odd length hex string"},
+		"nonHex":    {"zz", false, nil, "TEST_VAR is invalid: encoding/hex: invalid byte: U+007A 'z'"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			actual, err := ev.TryHexBytes()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				assert.Panics(t, func() { ev.HexBytes() })
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+		assert.Equal(t, []byte{0x01, 0x02}, genv.Var("MISSING_HEX").Default("0102").HexBytes())
+	})
 }
 
-func TestDefault(t *testing.T) {
-	allow := func(*Genv) bool { return true }
-	disallow := func(*Genv) bool { return false }
+func TestEvarTryIP(t *testing.T) {
 	for name, test := range map[string]struct {
-		found         bool
-		opts          []func(*Genv) bool
-		expectedValue string
+		value    string
+		optional bool
+		expected net.IP
+		err      string
 	}{
-		"Found":              {true, nil, "val"},
-		"FoundAllowed":       {true, []func(*Genv) bool{allow}, "val"},
-		"FoundDisallowed":    {true, []func(*Genv) bool{disallow}, "val"},
-		"NotFound":           {false, nil, "default"},
-		"NotFoundAllowed":    {false, []func(*Genv) bool{allow}, "default"},
-		"NotFoundDisallowed": {false, []func(*Genv) bool{disallow}, ""},
+		"IPv4":     {"10.0.0.1", false, net.ParseIP("10.0.0.1"), ""},
+		"IPv6":     {"::1", false, net.IPv6loopback, ""},
+		"empty":    {"", false, nil, "TEST_VAR is invalid: environment variable is empty or unset"},
+		"optional": {"", true, nil, ""},
+		"invalid":  {"10.0.0.256", false, nil, "TEST_VAR is invalid: invalid IP address: 10.0.0.256"},
 	} {
 		t.Run(name, func(t *testing.T) {
-			genv := New(WithAllowDefault(func(*Genv) bool { return true }))
-
-			if test.found {
-				t.Setenv("TEST_VAR", "val")
-			}
-
-			customOpt := new(MockDefaultOpt)
-			customOpt.On("optFunc")
-			opts := []defaultOpt{func(fb *fallback) { customOpt.optFunc() }}
-			fallbackOpts := make([]defaultOpt, len(test.opts))
-			for i, opt := range test.opts {
-				fallbackOpts[i] = genv.WithAllowDefault(opt)
+			ev := &Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			actual, err := ev.TryIP()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
 			}
-			opts = append(opts, fallbackOpts...)
-			actual := (*Var).Default(
-				genv.Var("TEST_VAR"),
-				"default",
-				opts...,
-			)
-			assert.Equal(t, test.expectedValue, actual.value)
-			customOpt.AssertExpectations(t)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
 		})
 	}
+
+	t.Run("Many", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "10.0.0.1,::1", splitKey: ","}
+		assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1"), net.IPv6loopback}, ev.ManyIP())
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "10.0.0.1,invalid", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyIP() })
+	})
 }
 
-func TestEVarString(t *testing.T) {
-	for _, test := range []struct {
-		name     string
+func TestEvarTryCIDR(t *testing.T) {
+	for name, test := range map[string]struct {
 		value    string
+		optional bool
 		expected string
-		panics   bool
+		err      string
 	}{
-		{"Valid", "val", "val", false},
-		{"Invalid", "", "", true},
+		"IPv4":      {"10.0.0.0/8", false, "10.0.0.0/8", ""},
+		"IPv6":      {"fd00::/8", false, "fd00::/8", ""},
+		"host bits": {"192.168.1.7/24", false, "192.168.1.0/24", ""},
+		"empty":     {"", false, "", "TEST_VAR is invalid: environment variable is empty or unset"},
+		"invalid":   {"10.0.0.0", false, "", "TEST_VAR is invalid: invalid CIDR address: 10.0.0.0"},
 	} {
-		t.Run(test.name, func(t *testing.T) {
-			ev := Var{key: "TEST_VAR", value: test.value}
-			if test.panics {
-				assert.Panics(t, func() { _ = ev.String() })
-			} else {
-				assert.Equal(t, test.expected, ev.String())
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			actual, err := ev.TryCIDR()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
 			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual.String())
 		})
 	}
-}
 
-func TestManyEvarString(t *testing.T) {
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "val1,val2", splitKey: ","}
-		assert.Equal(t, []string{"val1", "val2"}, ev.ManyString())
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", optional: true}
+		actual, err := ev.TryCIDR()
+		require.NoError(t, err)
+		assert.Nil(t, actual)
 	})
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: ""}
-		assert.Panics(t, func() { ev.ManyString() })
+	t.Run("Many", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "10.0.0.0/8;fd00::/8", splitKey: ";"}
+		networks := ev.ManyCIDR()
+		require.Len(t, networks, 2)
+		assert.Equal(t, "10.0.0.0/8", networks[0].String())
+		assert.Equal(t, "fd00::/8", networks[1].String())
 	})
 
-	t.Run(("Empty"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
-		assert.Empty(t, ev.ManyString())
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "10.0.0.0/33", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyCIDR() })
 	})
 }
 
-func TestEVarBool(t *testing.T) {
+func TestEvarDuration(t *testing.T) {
 	t.Run(("Valid"), func(t *testing.T) {
-		ev := Var{key: "TEST_VAR", value: "true"}
-		assert.True(t, ev.Bool())
-		ev.value = "false"
-		assert.False(t, ev.Bool())
+		ev := Var{key: "TEST_VAR", value: "1m30s"}
+		assert.Equal(t, 90*time.Second, ev.Duration())
 	})
 
 	t.Run(("Invalid"), func(t *testing.T) {
 		ev := Var{key: "TEST_VAR", value: "invalid"}
-		assert.Panics(t, func() { ev.Bool() })
+		assert.Panics(t, func() { ev.Duration() })
 	})
 }
 
-func TestEVarTryBool(t *testing.T) {
-	for _, test := range []struct {
-		name     string
+func TestEvarTryDuration(t *testing.T) {
+	for name, test := range map[string]struct {
 		value    string
-		expected bool
+		optional bool
+		expected time.Duration
 		err      bool
 	}{
-		{"ValidTrue", "true", true, false},
-		{"ValidFalse", "false", false, false},
-		{"Missing", "", false, true},
-		{"Invalid", "invalid", false, true},
+		"valid":    {"1m30s", false, 90 * time.Second, false},
+		"empty":    {"", false, 0, true},
+		"optional": {"", true, 0, false},
+		"invalid":  {"invalid", false, 0, true},
 	} {
-		t.Run(test.name, func(t *testing.T) {
-			ev := Var{key: "TEST_VAR", value: test.value}
-			actual, err := ev.TryBool()
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			actual, err := ev.TryDuration()
 			if test.err {
 				assert.Error(t, err)
 			} else {
@@ -206,347 +2272,779 @@ func TestEVarTryBool(t *testing.T) {
 	}
 }
 
-func TestManyEvarBool(t *testing.T) {
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "true,false", splitKey: ","}
-		assert.Equal(t, []bool{true, false}, ev.ManyBool())
+func TestTryManyEvarDuration(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected []time.Duration
+		err      bool
+	}{
+		"valid":    {"1s,1m30s", false, []time.Duration{time.Second, 90 * time.Second}, false},
+		"empty":    {"", false, nil, true},
+		"optional": {"", true, []time.Duration{}, false},
+		"invalid":  {"1s,invalid", false, nil, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, optional: test.optional, splitKey: ","}
+			actual, err := ev.TryManyDuration()
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", value: "invalid", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyDuration() })
 	})
+}
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "invalid"}
-		assert.Panics(t, func() { ev.ManyBool() })
+func TestEvarTryDurationBuckets(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected []time.Duration
+		err      string
+	}{
+		"ascending": {"5ms,10ms,25ms,50ms", []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond}, ""},
+		"single":    {"1s", []time.Duration{time.Second}, ""},
+		"outOfOrder": {"5ms,25ms,10ms,50ms", nil,
+			"BUCKETS is invalid: element 2 (10ms) is not greater than the one before it (25ms)"},
+		"repeated": {"5ms,5ms", nil,
+			"BUCKETS is invalid: element 1 (5ms) is not greater than the one before it (5ms)"},
+		"invalid": {"5ms,soon", nil,
+			`BUCKETS is invalid: element 1: time: invalid duration "soon"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "BUCKETS", value: test.value, splitKey: ","}
+			actual, err := ev.TryDurationBuckets()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				assert.Panics(t, func() { ev.DurationBuckets() })
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestEvarRound(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		round    time.Duration
+		expected time.Duration
+	}{
+		"millisecond": {"1234567ns", time.Millisecond, time.Millisecond},
+		"halfway":     {"1500us", time.Millisecond, 2 * time.Millisecond},
+		"second":      {"1m29.6s", time.Second, 90 * time.Second},
+		"disabled":    {"1234567ns", 0, 1234567 * time.Nanosecond},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := (&Var{key: "TIMEOUT", value: test.value}).Round(test.round)
+			assert.Equal(t, test.expected, ev.Duration())
+		})
+	}
+
+	t.Run("Many", func(t *testing.T) {
+		ev := (&Var{key: "TIMEOUTS", value: "1234567ns,2.6ms", splitKey: ","}).Round(time.Millisecond)
+		assert.Equal(t, []time.Duration{time.Millisecond, 3 * time.Millisecond}, ev.ManyDuration())
 	})
+}
 
-	t.Run(("Empty"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
-		assert.Empty(t, ev.ManyBool())
+func TestEvarClockDuration(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected time.Duration
+		err      bool
+	}{
+		"hours":        {"01:30:00", 90 * time.Minute, false},
+		"minutes":      {"02:15", 2*time.Minute + 15*time.Second, false},
+		"longLeading":  {"90:00", 90 * time.Minute, false},
+		"goSyntax":     {"1h30m", 90 * time.Minute, false},
+		"badMinutes":   {"01:60:00", 0, true},
+		"badSeconds":   {"01:xx", 0, true},
+		"negative":     {"-1:00", 0, true},
+		"emptyPart":    {"01::00", 0, true},
+		"tooManyParts": {"1:00:00:00", 0, true},
+		"signed":       {"+1:00", 0, true},
+		"signedLater":  {"1:+5", 0, true},
+		"overflow":     {"2562048:00:00", 0, true},
+		"maxHours":     {"2562047:00:00", 2562047 * time.Hour, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "BACKUP_AFTER", value: test.value}
+			actual, err := ev.ClockDuration().TryDuration()
+			if test.err {
+				assert.ErrorContains(t, err, "BACKUP_AFTER is invalid")
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		ev := &Var{key: "BACKUP_AFTER", value: "01:30:00"}
+		_, err := ev.TryDuration()
+		assert.Error(t, err)
 	})
 }
 
-func TestTryManyEvarBool(t *testing.T) {
-	for _, test := range []struct {
-		name     string
+func TestEvarTryTime(t *testing.T) {
+	layouts := []string{time.RFC3339, time.DateOnly, time.DateTime}
+	for name, test := range map[string]struct {
 		value    string
 		optional bool
-		expected []bool
+		expected time.Time
 		err      bool
 	}{
-		{"Valid", "true,false", false, []bool{true, false}, false},
-		{"Empty", "", false, []bool{}, true},
-		{"Optional", "", true, []bool{}, false},
-		{"Invalid", "invalid", false, []bool{}, true},
+		"rfc3339":  {"2024-03-01T12:30:00Z", false, time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), false},
+		"dateOnly": {"2024-03-01", false, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		"dateTime": {"2024-03-01 12:30:00", false, time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), false},
+		"noMatch":  {"03/01/2024", false, time.Time{}, true},
+		"empty":    {"", false, time.Time{}, true},
+		"optional": {"", true, time.Time{}, false},
 	} {
-		t.Run(test.name, func(t *testing.T) {
-			ev := &Var{key: "TEST_VAR", value: test.value, splitKey: ","}
-			if test.optional {
-				ev = ev.Optional()
-			}
-			actual, err := ev.TryManyBool()
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			actual, err := ev.TryTime(layouts...)
 			if test.err {
 				assert.Error(t, err)
 			} else {
 				assert.Nil(t, err)
-				assert.Equal(t, test.expected, actual)
+				assert.True(t, test.expected.Equal(actual), "expected %s, got %s", test.expected, actual)
 			}
 		})
 	}
-}
 
-func TestEvarInt(t *testing.T) {
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := Var{key: "TEST_VAR", value: "123"}
-		assert.Equal(t, 123, ev.Int())
+	t.Run("ErrorListsLayouts", func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "03/01/2024"}
+		_, err := ev.TryTime(time.DateOnly, time.DateTime)
+		assert.EqualError(t, err,
+			`TEST_VAR is invalid: "03/01/2024" does not match any of the layouts ["2006-01-02" "2006-01-02 15:04:05"]`)
 	})
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := Var{key: "TEST_VAR", value: "invalid"}
-		assert.Panics(t, func() { ev.Int() })
+	t.Run("DefaultLayout", func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "2024-03-01T12:30:00Z"}
+		assert.Equal(t, 2024, ev.Time().Year())
+		ev.value = "2024-03-01"
+		assert.Panics(t, func() { ev.Time() })
 	})
 }
 
-func TestEvarTryInt(t *testing.T) {
-	for _, test := range []struct {
-		name     string
+func TestEvarEveryPhrase(t *testing.T) {
+	for name, test := range map[string]struct {
 		value    string
-		optional bool
-		expected int
+		expected time.Duration
 		err      bool
 	}{
-		{"Valid", "123", false, 123, false},
-		{"Empty", "", false, 0, true},
-		{"Optional", "", true, 0, false},
-		{"Invalid", "invalid", false, 0, true},
+		"phrase":      {"every 30s", 30 * time.Second, false},
+		"minutes":     {"every 5m", 5 * time.Minute, false},
+		"plain":       {"30s", 30 * time.Second, false},
+		"unknown":     {"each 30s", 0, true},
+		"missingUnit": {"every 30", 0, true},
 	} {
-		t.Run(test.name, func(t *testing.T) {
-			ev := Var{key: "TEST_VAR", value: test.value}
-			if test.optional {
-				ev = *ev.Optional()
-			}
-			actual, err := ev.TryInt()
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "POLL", value: test.value}
+			actual, err := ev.EveryPhrase().TryDuration()
 			if test.err {
-				assert.Error(t, err)
+				assert.ErrorContains(t, err, "POLL is invalid")
 			} else {
 				assert.Nil(t, err)
 				assert.Equal(t, test.expected, actual)
 			}
 		})
 	}
-}
 
-func TestManyEvarInt(t *testing.T) {
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "123,456", splitKey: ","}
-		assert.Equal(t, []int{123, 456}, ev.ManyInt())
+	t.Run("Disabled", func(t *testing.T) {
+		ev := &Var{key: "POLL", value: "every 30s"}
+		_, err := ev.TryDuration()
+		assert.Error(t, err)
 	})
+}
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "invalid", splitKey: ","}
-		assert.Panics(t, func() { ev.ManyInt() })
-	})
+func TestEvarTrySchedule(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected []ScheduleStep
+		err      string
+	}{
+		"valid": {"1s:3,5s:2", false, []ScheduleStep{
+			{Interval: time.Second, Count: 3},
+			{Interval: 5 * time.Second, Count: 2},
+		}, ""},
+		"nonIntegerCount": {"1s:3,5s:two", false, nil, "INTERVALS is invalid: element 1: strconv.Atoi"},
+		"zeroCount":       {"1s:0", false, nil, "element 0: count 0 is not positive"},
+		"badInterval":     {"1x:3", false, nil, "element 0: time: unknown unit"},
+		"missingCount":    {"1s:3,5s", false, nil, `element 1: "5s" is not an interval:count pair`},
+		"empty":           {"", false, nil, "environment variable is empty or unset"},
+		"optional":        {"", true, []ScheduleStep{}, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "INTERVALS", value: test.value, optional: test.optional, splitKey: ","}
+			actual, err := ev.TrySchedule()
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
 
-	t.Run(("Empty"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
-		assert.Empty(t, ev.ManyInt())
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "INTERVALS", value: "1s", splitKey: ","}
+		assert.Panics(t, func() { ev.Schedule() })
 	})
 }
 
-func TestTryManyEvarInt(t *testing.T) {
+func TestEvarTryDurationClamp(t *testing.T) {
 	for name, test := range map[string]struct {
 		value    string
-		optional bool
-		expected []int
-		err      bool
+		expected time.Duration
+		logged   bool
 	}{
-		"valid":    {"123,456", false, []int{123, 456}, false},
-		"empty":    {"", false, []int{}, true},
-		"optional": {"", true, []int{}, false},
-		"invalid":  {"invalid", false, []int{}, true},
+		"below":   {"100ms", time.Second, true},
+		"above":   {"5m", time.Minute, true},
+		"inRange": {"30s", 30 * time.Second, false},
+		"atBound": {"1m", time.Minute, false},
 	} {
 		t.Run(name, func(t *testing.T) {
-			ev := &Var{key: "TEST_VAR", value: test.value, splitKey: ","}
-			if test.optional {
-				ev = ev.Optional()
+			var buf bytes.Buffer
+			genv := New(WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+			ev := Var{key: "TEST_VAR", value: test.value, genv: genv}
+			actual, err := ev.TryDurationClamp(time.Second, time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+			if test.logged {
+				assert.Contains(t, buf.String(), "level=WARN")
+				assert.Contains(t, buf.String(), "key=TEST_VAR")
+				assert.Contains(t, buf.String(), "clamped="+test.expected.String())
+			} else {
+				assert.Empty(t, buf.String())
 			}
-			actual, err := ev.TryManyInt()
+		})
+	}
+
+	t.Run("NoLogger", func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "5m"}
+		assert.Equal(t, time.Minute, ev.DurationClamp(time.Second, time.Minute))
+	})
+
+	t.Run("InvalidBounds", func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "5m"}
+		_, err := ev.TryDurationClamp(time.Minute, time.Second)
+		assert.Error(t, err)
+	})
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", optional: true}
+		actual, err := ev.TryDurationClamp(time.Second, time.Minute)
+		assert.NoError(t, err)
+		assert.Zero(t, actual)
+	})
+}
+
+func TestTryManyChoiceLenient(t *testing.T) {
+	type level int
+	mapping := map[string]level{"debug": 0, "info": 1, "warn": 2}
+
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		known    []level
+		unknown  []string
+		err      bool
+	}{
+		"mixed":    {"debug,trace,warn,fatal", false, []level{0, 2}, []string{"trace", "fatal"}, false},
+		"known":    {"info,warn", false, []level{1, 2}, nil, false},
+		"unknown":  {"trace", false, []level{}, []string{"trace"}, false},
+		"empty":    {"", false, nil, nil, true},
+		"optional": {"", true, []level{}, nil, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "TEST_VAR", value: test.value, optional: test.optional, splitKey: ","}
+			known, unknown, err := TryManyChoiceLenient(ev, mapping)
 			if test.err {
 				assert.Error(t, err)
-			} else {
-				assert.Nil(t, err)
-				assert.Equal(t, test.expected, actual)
+				return
 			}
+			require.NoError(t, err)
+			assert.Equal(t, test.known, known)
+			assert.Equal(t, test.unknown, unknown)
 		})
 	}
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "TEST_VAR", splitKey: ","}
+		assert.Panics(t, func() { ManyChoiceLenient(ev, mapping) })
+	})
 }
 
-func TestEvarFloat64(t *testing.T) {
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := Var{key: "TEST_VAR", value: "123.456"}
-		assert.Equal(t, 123.456, ev.Float64())
+func TestCaseFold(t *testing.T) {
+	levels := map[string]string{"DEBUG": "DEBUG", "INFO": "INFO"}
+
+	t.Run("Folded", func(t *testing.T) {
+		ev := (&Var{key: "LOG_LEVELS", value: "Info,debug,trace", splitKey: ","}).CaseFold()
+		known, unknown := ManyChoiceLenient(ev, levels)
+		assert.Equal(t, []string{"INFO", "DEBUG"}, known)
+		assert.Equal(t, []string{"trace"}, unknown)
 	})
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := Var{key: "TEST_VAR", value: "invalid"}
-		assert.Panics(t, func() { ev.Float64() })
+	t.Run("Exact", func(t *testing.T) {
+		ev := &Var{key: "LOG_LEVELS", value: "Info,INFO", splitKey: ","}
+		known, unknown := ManyChoiceLenient(ev, levels)
+		assert.Equal(t, []string{"INFO"}, known)
+		assert.Equal(t, []string{"Info"}, unknown)
 	})
 }
 
-func TestEvarTryFloat64(t *testing.T) {
+func TestEvarTryPath(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "a.so")
+	require.NoError(t, os.WriteFile(existing, nil, 0o600))
+	missing := filepath.Join(dir, "b.so")
+
 	for name, test := range map[string]struct {
-		value    string
-		optional bool
-		expected float64
-		err      bool
+		value     string
+		mustExist bool
+		err       bool
 	}{
-		"valid":    {"123.456", false, 123.456, false},
-		"empty":    {"", false, 0.0, true},
-		"optional": {"", true, 0.0, false},
-		"invalid":  {"invalid", false, 0.0, true},
+		"existing":       {existing, true, false},
+		"missing":        {missing, true, true},
+		"missingAllowed": {missing, false, false},
 	} {
 		t.Run(name, func(t *testing.T) {
-			ev := Var{key: "TEST_VAR", value: test.value}
-			if test.optional {
-				ev = *ev.Optional()
+			ev := &Var{key: "TEST_VAR", value: test.value}
+			if test.mustExist {
+				ev = ev.MustExist()
 			}
-			actual, err := ev.TryFloat64()
+			actual, err := ev.TryPath()
 			if test.err {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, fs.ErrNotExist)
 			} else {
 				assert.Nil(t, err)
-				assert.Equal(t, test.expected, actual)
+				assert.Equal(t, test.value, actual)
 			}
 		})
 	}
-}
 
-func TestEvarManyFloat64(t *testing.T) {
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "123.456,456.789", splitKey: ","}
-		assert.Equal(t, []float64{123.456, 456.789}, ev.ManyFloat64())
+	t.Run("Many", func(t *testing.T) {
+		ev := &Var{key: "PLUGINS", value: existing + "," + existing, splitKey: ","}
+		assert.Equal(t, []string{existing, existing}, ev.MustExist().ManyPath())
 	})
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "invalid", splitKey: ","}
-		assert.Panics(t, func() { ev.ManyFloat64() })
+	t.Run("ManyMissing", func(t *testing.T) {
+		ev := &Var{key: "PLUGINS", value: existing + "," + missing, splitKey: ","}
+		_, err := ev.MustExist().TryManyPath()
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.ErrorContains(t, err, "PLUGINS is invalid: element 1: stat "+missing)
 	})
+}
 
-	t.Run(("Empty"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
-		assert.Empty(t, ev.ManyFloat64())
+func TestEvarTryInterface(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+	idx := slices.IndexFunc(ifaces, func(iface net.Interface) bool {
+		return iface.Flags&net.FlagLoopback != 0
 	})
-}
+	if idx < 0 {
+		t.Skip("no loopback interface available")
+	}
+	loopback := ifaces[idx].Name
 
-func TestTryManyEvarFloat64(t *testing.T) {
-	for _, test := range []struct {
-		name     string
+	for name, test := range map[string]struct {
 		value    string
 		optional bool
-		expected []float64
+		expected string
 		err      bool
 	}{
-		{"Valid", "123.456,456.789", false, []float64{123.456, 456.789}, false},
-		{"Empty", "", false, []float64{}, true},
-		{"Optional", "", true, []float64{}, false},
-		{"Invalid", "invalid", false, []float64{}, true},
+		"loopback": {loopback, false, loopback, false},
+		"missing":  {"genv-missing0", false, "", true},
+		"empty":    {"", false, "", true},
+		"optional": {"", true, "", false},
 	} {
-		t.Run(test.name, func(t *testing.T) {
-			ev := &Var{key: "TEST_VAR", value: test.value, splitKey: ","}
-			if test.optional {
-				ev = ev.Optional()
-			}
-			actual, err := ev.TryManyFloat64()
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			actual, err := ev.TryInterface()
 			if test.err {
 				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			if test.expected == "" {
+				assert.Nil(t, actual)
 			} else {
-				assert.Nil(t, err)
-				assert.Equal(t, test.expected, actual)
+				assert.Equal(t, test.expected, actual.Name)
 			}
 		})
 	}
-}
 
-func TestEvarURL(t *testing.T) {
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := Var{key: "TEST_VAR", value: "http://example.com:8080"}
-		url := ev.URL()
-		assert.Equal(t, "http", url.Scheme)
-		assert.Equal(t, "example.com", url.Hostname())
-		assert.Equal(t, "8080", url.Port())
-		assert.Equal(t, "http://example.com:8080", ev.URL().String())
+	t.Run("Panics", func(t *testing.T) {
+		ev := Var{key: "TEST_VAR", value: "genv-missing0"}
+		assert.Panics(t, func() { ev.Interface() })
 	})
+}
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := Var{key: "TEST_VAR", value: "http://invalid url"}
-		assert.Panics(t, func() { ev.URL() })
-	})
+func TestUnique(t *testing.T) {
+	for name, test := range map[string]struct {
+		nodeID  *string
+		peerIDs *string
+		err     string
+	}{
+		"Duplicated": {ptr("node-2"), ptr("node-1,node-2,node-3"), `NODE_ID is invalid: "node-2" conflicts with an element of PEER_IDS`},
+		"Absent":     {ptr("node-4"), ptr("node-1,node-2,node-3"), ""},
+		"NoValue":    {nil, ptr("node-1"), ""},
+		"NoSet":      {ptr("node-1"), nil, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if test.nodeID != nil {
+				t.Setenv("NODE_ID", *test.nodeID)
+			}
+			if test.peerIDs != nil {
+				t.Setenv("PEER_IDS", *test.peerIDs)
+			}
+			err := New().Unique("NODE_ID", "PEER_IDS")
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
 
-func TestEvarTryURL(t *testing.T) {
-	for _, test := range []struct {
-		name     string
+func TestEvarTryRegexp(t *testing.T) {
+	for name, test := range map[string]struct {
 		value    string
 		optional bool
 		expected string
 		err      bool
 	}{
-		{"Valid", "http://example.com:8080", false, "http://example.com:8080", false},
-		{"Empty", "", false, "", true},
-		{"Optional", "", true, "", false},
-		{"Invalid", "http://invalid url", false, "", true},
+		"valid":    {"^/api/.*", false, "^/api/.*", false},
+		"invalid":  {"^/api/(", false, "", true},
+		"empty":    {"", false, "", true},
+		"optional": {"", true, "", false},
 	} {
-		t.Run(test.name, func(t *testing.T) {
-			ev := Var{key: "TEST_VAR", value: test.value}
-			if test.optional {
-				ev = *ev.Optional()
-			}
-			actual, err := ev.TryURL()
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "TEST_VAR", value: test.value, optional: test.optional}
+			actual, err := ev.TryRegexp()
 			if test.err {
 				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			if test.expected == "" {
+				assert.Nil(t, actual)
 			} else {
-				assert.Nil(t, err)
-				if test.expected == "" {
-					assert.Nil(t, actual)
-				} else {
-					assert.Equal(t, test.expected, actual.String())
-				}
+				assert.Equal(t, test.expected, actual.String())
 			}
 		})
 	}
 }
 
-func TestManyEvarURL(t *testing.T) {
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "http://example.com:8080,http://example.com:8081", splitKey: ","}
-		urls := ev.ManyURL()
-		assert.Equal(t, "http://example.com:8080", urls[0].String())
-		assert.Equal(t, "http://example.com:8081", urls[1].String())
+func TestManyEvarRegexp(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "ROUTES", value: "^/api/.*,^/admin/.*", splitKey: ","}
+		routes := ev.ManyRegexp()
+		require.Len(t, routes, 2)
+		assert.True(t, routes[0].MatchString("/api/users"))
+		assert.False(t, routes[0].MatchString("/admin"))
+		assert.True(t, routes[1].MatchString("/admin/users"))
 	})
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "http://invalid url", splitKey: ","}
-		assert.Panics(t, func() { ev.ManyURL() })
+	t.Run("InvalidMiddle", func(t *testing.T) {
+		ev := &Var{key: "ROUTES", value: "^/api/.*,^/(admin,^/health$", splitKey: ","}
+		_, err := ev.TryManyRegexp()
+		assert.ErrorContains(t, err, "ROUTES is invalid: element 1: error parsing regexp")
 	})
 
-	t.Run(("Empty"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
-		assert.Empty(t, ev.ManyURL())
+	t.Run("PositionSkipsEmpty", func(t *testing.T) {
+		ev := &Var{key: "ROUTES", value: "^/api/.*,,^/(admin", splitKey: ","}
+		_, err := ev.TryManyRegexp()
+		assert.ErrorContains(t, err, "element 2:")
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "ROUTES", value: "^/(admin", splitKey: ","}
+		assert.Panics(t, func() { ev.ManyRegexp() })
 	})
 }
 
-func TestTryManyEvarURL(t *testing.T) {
+func TestManyAllocations(t *testing.T) {
+	value := largeList(10_000)
+	allocs := testing.AllocsPerRun(10, func() {
+		ev := &Var{key: "TEST_VAR", value: value, splitKey: ","}
+		_ = ev.ManyInt()
+	})
+	// Allocations must not grow with the number of elements.
+	assert.LessOrEqual(t, allocs, 5.0)
+}
+
+func BenchmarkManyInt(b *testing.B) {
+	value := largeList(10_000)
+	b.ReportAllocs()
+	for range b.N {
+		ev := &Var{key: "TEST_VAR", value: value, splitKey: ","}
+		_ = ev.ManyInt()
+	}
+}
+
+func largeList(n int) string {
+	elements := make([]string, n)
+	for i := range elements {
+		elements[i] = strconv.Itoa(i)
+	}
+	return strings.Join(elements, ",")
+}
+
+func TestAllOrNone(t *testing.T) {
 	for name, test := range map[string]struct {
-		value    string
-		optional bool
-		expected []string
-		err      bool
+		env map[string]string
+		err string
 	}{
-		"valid": {
-			"http://example.com:8080,http://example.com:8081",
-			false,
-			[]string{
-				"http://example.com:8080",
-				"http://example.com:8081"},
-			false,
+		"AllSet":  {map[string]string{"TLS_CERT": "cert", "TLS_KEY": "key", "TLS_CA": "ca"}, ""},
+		"NoneSet": {map[string]string{}, ""},
+		"Partial": {
+			map[string]string{"TLS_CERT": "cert", "TLS_KEY": ""},
+			"TLS_CERT, TLS_KEY, TLS_CA must be set together: set TLS_CERT; missing TLS_KEY, TLS_CA",
 		},
-		"empty":    {"", false, nil, true},
-		"optional": {"", true, nil, false},
-		"invalid":  {"http://invalid url", false, nil, true},
 	} {
 		t.Run(name, func(t *testing.T) {
-			ev := Var{key: "TEST_VAR", value: test.value, splitKey: ","}
-			if test.optional {
-				ev = *ev.Optional()
-			}
-			actual, err := ev.TryManyURL()
-			if test.err {
-				assert.Error(t, err)
-				return
+			for key, value := range test.env {
+				t.Setenv(key, value)
 			}
-			require.Nil(t, err)
-			if test.expected == nil {
-				assert.Equal(t, []*url.URL{}, actual)
-				return
+			err := New().AllOrNone("TLS_CERT", "TLS_KEY", "TLS_CA")
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
 			}
-			for i, url := range actual {
-				assert.Equal(t, test.expected[i], url.String())
+		})
+	}
+
+	t.Run("Named", func(t *testing.T) {
+		t.Setenv("TLS_CERT", "cert")
+		err := New(WithName("api")).AllOrNone("TLS_CERT", "TLS_KEY")
+		assert.EqualError(t, err, "api: TLS_CERT, TLS_KEY must be set together: set TLS_CERT; missing TLS_KEY")
+	})
+}
+
+func TestValidate(t *testing.T) {
+	bounded := func(p int) error {
+		if p < 1024 {
+			return fmt.Errorf("%d is a privileged port", p)
+		}
+		return nil
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		ev := Validate(&Var{key: "PORT", value: "8080"}, bounded)
+		assert.Equal(t, 8080, ev.Int())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		ev := Validate(&Var{key: "PORT", value: "80"}, bounded)
+		_, err := ev.TryInt()
+		assert.EqualError(t, err, "PORT is invalid: 80 is a privileged port")
+		assert.Panics(t, func() { ev.Int() })
+	})
+
+	t.Run("Chained", func(t *testing.T) {
+		genv := New()
+		ev := Validate(genv.Var("PORT", func(v *Var) { v.value = "9000" }), bounded)
+		ev = Validate(ev, func(p int) error {
+			if p%2 != 0 {
+				return errors.New("must be even")
 			}
+			return nil
+		})
+		assert.Equal(t, 9000, ev.Optional().Int())
+	})
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := Validate((&Var{key: "PORT"}).Optional(), bounded)
+		assert.Equal(t, 0, ev.Int())
+	})
+
+	t.Run("Elements", func(t *testing.T) {
+		ev := Validate(&Var{key: "PORTS", value: "8080,22", splitKey: ","}, bounded)
+		_, err := ev.TryManyInt()
+		assert.EqualError(t, err, "PORTS is invalid: element 1: 22 is a privileged port")
+	})
+
+	t.Run("List", func(t *testing.T) {
+		ev := Validate(&Var{key: "PORTS", value: "8080,9090", splitKey: ","}, func(ports []int) error {
+			return fmt.Errorf("got %d ports", len(ports))
+		})
+		_, err := ev.TryManyInt()
+		assert.EqualError(t, err, "PORTS is invalid: got 2 ports")
+	})
+
+	t.Run("Converted", func(t *testing.T) {
+		ev := Validate(&Var{key: "NAME", value: "db"}, func(name string) error {
+			return errors.New("unknown name")
 		})
+		_, err := TryAs(ev, (*Var).TryString, func(s string) (int, error) { return len(s), nil })
+		assert.EqualError(t, err, "NAME is invalid: unknown name")
+	})
+
+	t.Run("ConvertedOneOf", func(t *testing.T) {
+		ev := OneOf(&Var{key: "REGION", value: "us"}, "us", "eu")
+		actual, err := TryAs(ev, (*Var).TryString, url.Parse)
+		require.NoError(t, err)
+		assert.Equal(t, "us", actual.String())
+	})
+
+	t.Run("Mismatched", func(t *testing.T) {
+		ev := Validate(&Var{key: "PORT", value: "80"}, func(int64) error { return nil })
+		_, err := ev.TryInt()
+		assert.EqualError(t, err, "PORT is invalid: a validator for int64 cannot check int")
+
+		ev = Validate(&Var{key: "PORTS", value: "80", splitKey: ","}, func(int64) error { return nil })
+		_, err = ev.TryManyInt()
+		assert.ErrorContains(t, err, "a validator for int64 cannot check int")
+
+		ev = Validate(&Var{key: "REGION", value: "us"}, func(*url.URL) error { return nil })
+		_, err = TryAs(ev, (*Var).TryString, url.Parse)
+		assert.EqualError(t, err, "REGION is invalid: a validator for *url.URL cannot check string")
+	})
+
+	t.Run("MapValues", func(t *testing.T) {
+		ev := Validate(&Var{key: "LIMITS", value: "a=1,b=2000", splitKey: ","}, bounded)
+		_, err := TryMap(ev, (*Var).TryInt)
+		assert.EqualError(t, err, `LIMITS is invalid: key "a": 1 is a privileged port`)
+	})
+}
+
+func TestOneOf(t *testing.T) {
+	t.Run("Allowed", func(t *testing.T) {
+		ev := OneOf(&Var{key: "LOG_LEVEL", value: "INFO"}, "DEBUG", "INFO")
+		assert.Equal(t, "INFO", ev.String())
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		ev := OneOf(&Var{key: "LOG_LEVEL", value: "Info"}, "DEBUG", "INFO")
+		_, err := ev.TryString()
+		assert.EqualError(t, err, "LOG_LEVEL is invalid: Info is not one of [DEBUG INFO]")
+	})
+
+	t.Run("CaseFold", func(t *testing.T) {
+		ev := OneOf(&Var{key: "LOG_LEVEL", value: "Info"}, "DEBUG", "INFO").CaseFold()
+		assert.Equal(t, "INFO", ev.String())
+	})
+
+	t.Run("CaseFoldNamed", func(t *testing.T) {
+		type level string
+		parseLevel := func(ev *Var) (level, error) {
+			s, err := ev.TryString()
+			return level(s), err
+		}
+
+		ev := OneOf[level](&Var{key: "LEVELS", value: "info,Debug", splitKey: ","}, "DEBUG", "INFO").CaseFold()
+		levels, err := TryMany(ev, parseLevel)
+		require.NoError(t, err)
+		assert.Equal(t, []level{"INFO", "DEBUG"}, levels)
+	})
+
+	t.Run("Int", func(t *testing.T) {
+		ev := OneOf(&Var{key: "WORKERS", value: "3"}, 1, 2, 4)
+		_, err := ev.TryInt()
+		assert.EqualError(t, err, "WORKERS is invalid: 3 is not one of [1 2 4]")
+	})
+
+	t.Run("Elements", func(t *testing.T) {
+		ev := OneOf(&Var{key: "ENVS", value: "dev,Prod", splitKey: ","}, "dev", "prod").CaseFold()
+		assert.Equal(t, []string{"dev", "prod"}, ev.ManyString())
+	})
+}
+
+func TestTryJSON(t *testing.T) {
+	type features struct {
+		A bool `json:"a"`
+		B int  `json:"b"`
 	}
 
-	t.Run(("Valid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "http://example.com:8080,http://example.com:8081", splitKey: ","}
-		urls := ev.ManyURL()
-		assert.Equal(t, "http://example.com:8080", urls[0].String())
-		assert.Equal(t, "http://example.com:8081", urls[1].String())
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "FEATURE_MATRIX", value: `{"a":true,"b":2}`}
+		actual, err := TryJSON[features](ev)
+		require.NoError(t, err)
+		assert.Equal(t, features{A: true, B: 2}, actual)
 	})
 
-	t.Run(("Invalid"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "http://invalid url", splitKey: ","}
-		assert.Panics(t, func() { ev.ManyURL() })
+	t.Run("Map", func(t *testing.T) {
+		ev := &Var{key: "FEATURE_MATRIX", value: `{"a":true}`}
+		assert.Equal(t, map[string]bool{"a": true}, JSON[map[string]bool](ev))
 	})
 
-	t.Run(("Empty"), func(t *testing.T) {
-		ev := &Var{key: "TEST_VAR", value: "", optional: true, splitKey: ","}
-		assert.Empty(t, ev.ManyURL())
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "FEATURE_MATRIX", optional: true}
+		actual, err := TryJSON[features](ev)
+		require.NoError(t, err)
+		assert.Zero(t, actual)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		genv := New(WithAllowDefault(func(*Genv) bool { return true }))
+		ev := genv.Var("FEATURE_MATRIX").Default(`{"b":1}`)
+		assert.Equal(t, features{B: 1}, JSON[features](ev))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		ev := &Var{key: "FEATURE_MATRIX", value: `{"a":"yes"}`}
+		_, err := TryJSON[features](ev)
+		assert.ErrorContains(t, err, "FEATURE_MATRIX is invalid: json: cannot unmarshal")
+		assert.Panics(t, func() { JSON[features](ev) })
+	})
+}
+
+func TestTryAs(t *testing.T) {
+	endpoints := map[string]string{
+		"us": "https://us.example.com",
+		"eu": "https://eu.example.com",
+	}
+	endpointFor := func(region string) (*url.URL, error) {
+		endpoint, ok := endpoints[region]
+		if !ok {
+			return nil, fmt.Errorf("unknown region %q", region)
+		}
+		return url.Parse(endpoint)
+	}
+
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		expected string
+		err      string
+	}{
+		"known":    {"eu", false, "https://eu.example.com", ""},
+		"unknown":  {"ap", false, "", `REGION is invalid: unknown region "ap"`},
+		"empty":    {"", false, "", "REGION is invalid: environment variable is empty or unset"},
+		"optional": {"", true, "", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "REGION", value: test.value, optional: test.optional}
+			actual, err := TryAs(ev, (*Var).TryString, endpointFor)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.Nil(t, err)
+			if test.expected == "" {
+				assert.Nil(t, actual)
+			} else {
+				assert.Equal(t, test.expected, actual.String())
+			}
+		})
+	}
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "REGION", value: "ap"}
+		assert.Panics(t, func() { As(ev, (*Var).TryString, endpointFor) })
 	})
 }
 
@@ -574,3 +3072,7 @@ func TestPresent(t *testing.T) {
 func newGenv() *Genv {
 	return New(WithAllowDefault(func(*Genv) bool { return true }))
 }
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,59 @@
+// Package genvtest provides helpers for testing code that reads
+// environment variables.
+package genvtest
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// Snapshots the process environment and restores it once the test and its
+// subtests have finished, so the test may call os.Setenv and os.Unsetenv
+// freely without leaking state into other tests. Like t.Setenv, it must not
+// be used in parallel tests.
+func Freeze(t testing.TB) {
+	t.Helper()
+	snapshot := os.Environ()
+	t.Cleanup(func() {
+		os.Clearenv()
+		for _, entry := range snapshot {
+			key, value, ok := splitEntry(entry)
+			if !ok {
+				continue
+			}
+			if err := os.Setenv(key, value); err != nil {
+				t.Errorf("restoring %s: %v", key, err)
+			}
+		}
+	})
+}
+
+// Splits a "KEY=value" entry of os.Environ. The first byte is skipped so
+// that Windows entries such as "=C:=C:\" keep their leading "=" in the key,
+// and entries without a "=", including empty ones, are rejected.
+func splitEntry(entry string) (key, value string, ok bool) {
+	i := strings.Index(entry[min(1, len(entry)):], "=") + 1
+	if i == 0 {
+		return "", "", false
+	}
+	return entry[:i], entry[i+1:], true
+}
+
+// Checks that parse(format(v)) == v for each value of an enum, so that the
+// functions used to read the enum, such as the convert function given to
+// genv.As, and to write it agree. Each mismatch or parse error is reported
+// with t.Errorf.
+func AssertEnumRoundTrip[T comparable](t testing.TB, parse func(string) (T, error), format func(T) string, values ...T) {
+	t.Helper()
+	for _, want := range values {
+		formatted := format(want)
+		got, err := parse(formatted)
+		switch {
+		case err != nil:
+			t.Errorf("parsing %q formatted from %v: %v", formatted, want, err)
+		case got != want:
+			t.Errorf("%v formatted as %q parses as %v", want, formatted, got)
+		}
+	}
+}
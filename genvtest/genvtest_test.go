@@ -0,0 +1,132 @@
+package genvtest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/rlebel12/genv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeze(t *testing.T) {
+	t.Setenv("GENVTEST_EXISTING", "original")
+
+	t.Run("Mutate", func(t *testing.T) {
+		Freeze(t)
+		require.NoError(t, os.Setenv("GENVTEST_ADDED", "added"))
+		require.NoError(t, os.Setenv("GENVTEST_EXISTING", "changed"))
+		assert.Equal(t, "added", os.Getenv("GENVTEST_ADDED"))
+	})
+
+	t.Run("Isolated", func(t *testing.T) {
+		_, found := os.LookupEnv("GENVTEST_ADDED")
+		assert.False(t, found)
+		assert.Equal(t, "original", os.Getenv("GENVTEST_EXISTING"))
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		Freeze(t)
+		require.NoError(t, os.Unsetenv("GENVTEST_EXISTING"))
+	})
+
+	t.Run("Restored", func(t *testing.T) {
+		assert.Equal(t, "original", os.Getenv("GENVTEST_EXISTING"))
+	})
+}
+
+func TestSplitEntry(t *testing.T) {
+	for entry, expected := range map[string][]string{
+		"KEY=value": {"KEY", "value"},
+		"KEY=a=b":   {"KEY", "a=b"},
+		"KEY=":      {"KEY", ""},
+		"=C:=C:\\":  {"=C:", "C:\\"},
+		"MALFORMED": nil,
+		"":          nil,
+	} {
+		t.Run(entry, func(t *testing.T) {
+			key, value, ok := splitEntry(entry)
+			if expected == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, expected, []string{key, value})
+		})
+	}
+}
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+func ParsePriority(value string) (Priority, error) {
+	switch value {
+	case "low":
+		return PriorityLow, nil
+	case "normal":
+		return PriorityNormal, nil
+	case "high":
+		return PriorityHigh, nil
+	}
+	return 0, fmt.Errorf("unknown priority %q", value)
+}
+
+// Records failures instead of failing the test, so that failing checks can
+// be asserted on.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEnumRoundTrip(t *testing.T) {
+	t.Run("Consistent", func(t *testing.T) {
+		AssertEnumRoundTrip(t, ParsePriority, Priority.String,
+			PriorityLow, PriorityNormal, PriorityHigh)
+	})
+
+	t.Run("Inconsistent", func(t *testing.T) {
+		// Formats every priority as "normal", so only PriorityNormal survives.
+		format := func(Priority) string { return "normal" }
+		tb := &recordingTB{TB: t}
+		AssertEnumRoundTrip(tb, ParsePriority, format, PriorityLow, PriorityNormal)
+		assert.Equal(t, []string{`low formatted as "normal" parses as normal`}, tb.errors)
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		tb := &recordingTB{TB: t}
+		AssertEnumRoundTrip(tb, ParsePriority, Priority.String, Priority(7))
+		assert.Equal(t, []string{
+			`parsing "Priority(7)" formatted from Priority(7): unknown priority "Priority(7)"`,
+		}, tb.errors)
+	})
+
+	t.Run("WithAs", func(t *testing.T) {
+		t.Setenv("PRIORITY", "high")
+		priority := genv.As(genv.New().Var("PRIORITY"), (*genv.Var).TryString, ParsePriority)
+		assert.Equal(t, PriorityHigh, priority)
+	})
+}
@@ -0,0 +1,56 @@
+package genv
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const defaultIDCharset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// Describes identifiers made of a fixed prefix followed by a suffix, such
+// as "t_ab12cd". The suffix may only contain characters from Charset, which
+// defaults to lowercase letters and digits, and its length must fall within
+// [MinLength, MaxLength]. A zero MaxLength leaves the length unbounded.
+type IDFormat struct {
+	Prefix    string
+	Charset   string
+	MinLength int
+	MaxLength int
+}
+
+func (format IDFormat) parse(value string) (string, error) {
+	suffix, ok := strings.CutPrefix(value, format.Prefix)
+	if !ok {
+		return "", format.mismatch(value)
+	}
+
+	length := utf8.RuneCountInString(suffix)
+	if length < format.MinLength || (format.MaxLength > 0 && length > format.MaxLength) {
+		return "", format.mismatch(value)
+	}
+
+	charset := format.charset()
+	for _, r := range suffix {
+		if !strings.ContainsRune(charset, r) {
+			return "", format.mismatch(value)
+		}
+	}
+	return value, nil
+}
+
+func (format IDFormat) charset() string {
+	if format.Charset == "" {
+		return defaultIDCharset
+	}
+	return format.Charset
+}
+
+func (format IDFormat) mismatch(value string) error {
+	length := fmt.Sprintf("at least %d", format.MinLength)
+	if format.MaxLength > 0 {
+		length = fmt.Sprintf("%d to %d", format.MinLength, format.MaxLength)
+	}
+	return fmt.Errorf("%q does not match the expected format: %q followed by %s characters from %q",
+		value, format.Prefix, length, format.charset())
+}
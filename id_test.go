@@ -0,0 +1,53 @@
+package genv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvarTryPrefixedID(t *testing.T) {
+	format := IDFormat{Prefix: "t_", MinLength: 6, MaxLength: 8}
+	for name, test := range map[string]struct {
+		value    string
+		optional bool
+		err      bool
+	}{
+		"valid":       {"t_ab12cd", false, false},
+		"maxLength":   {"t_ab12cd34", false, false},
+		"wrongPrefix": {"u_ab12cd", false, true},
+		"tooShort":    {"t_ab1", false, true},
+		"tooLong":     {"t_ab12cd345", false, true},
+		"badCharset":  {"t_AB12CD", false, true},
+		"empty":       {"", false, true},
+		"optional":    {"", true, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "TENANT_ID", value: test.value, optional: test.optional}
+			actual, err := ev.TryPrefixedID(format)
+			if test.err {
+				assert.ErrorContains(t, err, "TENANT_ID is invalid")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.value, actual)
+		})
+	}
+
+	t.Run("ErrorDescribesFormat", func(t *testing.T) {
+		ev := Var{key: "TENANT_ID", value: "t_ab1"}
+		_, err := ev.TryPrefixedID(format)
+		assert.EqualError(t, err, `TENANT_ID is invalid: "t_ab1" does not match the expected format: `+
+			`"t_" followed by 6 to 8 characters from "abcdefghijklmnopqrstuvwxyz0123456789"`)
+	})
+
+	t.Run("CustomCharset", func(t *testing.T) {
+		ev := Var{key: "TENANT_ID", value: "T-0F0F"}
+		assert.Equal(t, "T-0F0F", ev.PrefixedID(IDFormat{Prefix: "T-", Charset: "0123456789ABCDEF"}))
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := Var{key: "TENANT_ID", value: "u_ab12cd"}
+		assert.Panics(t, func() { ev.PrefixedID(format) })
+	})
+}
@@ -0,0 +1,133 @@
+package genv
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Populates the exported fields of the struct pointed to by v from the
+// environment. Each field is read from the key given by its `env` tag or,
+// without one, from its name converted to SCREAMING_SNAKE_CASE, so that
+// DatabaseURL is read from DATABASE_URL. A tag of "-" skips the field, and
+// a ",optional" suffix marks it as optional:
+//
+//	type Config struct {
+//		DatabaseURL *url.URL
+//		Port        int    `env:"HTTP_PORT"`
+//		LogLevel    string `env:",optional"`
+//	}
+//
+// Supported field types are string, bool, int, int64, uint, uint64,
+// float64, time.Duration, *url.URL, slices of these, and
+// map[string]string. Every field is attempted, and the failures are
+// returned together.
+func (genv *Genv) ParseInto(v any) error {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ParseInto requires a pointer to a struct, got %T", v)
+	}
+
+	target := ptr.Elem()
+	var errs []error
+	for i := range target.NumField() {
+		field := target.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("env")
+		if tag == "-" {
+			continue
+		}
+		key, opts, _ := strings.Cut(tag, ",")
+		if key == "" {
+			key = screamingSnakeCase(field.Name)
+		}
+
+		ev := genv.Var(key)
+		if opts == "optional" {
+			ev.Optional()
+		}
+		if err := setField(target.Field(i), ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setField(field reflect.Value, ev *Var) error {
+	var result any
+	var err error
+	switch field.Type() {
+	case reflect.TypeFor[time.Duration]():
+		result, err = ev.TryDuration()
+	case reflect.TypeFor[[]time.Duration]():
+		result, err = ev.TryManyDuration()
+	case reflect.TypeFor[*url.URL]():
+		result, err = ev.TryURL()
+	case reflect.TypeFor[[]*url.URL]():
+		result, err = ev.TryManyURL()
+	case reflect.TypeFor[string]():
+		result, err = ev.TryString()
+	case reflect.TypeFor[[]string]():
+		result, err = ev.TryManyString()
+	case reflect.TypeFor[bool]():
+		result, err = ev.TryBool()
+	case reflect.TypeFor[[]bool]():
+		result, err = ev.TryManyBool()
+	case reflect.TypeFor[int]():
+		result, err = ev.TryInt()
+	case reflect.TypeFor[[]int]():
+		result, err = ev.TryManyInt()
+	case reflect.TypeFor[int64]():
+		result, err = ev.TryInt64()
+	case reflect.TypeFor[[]int64]():
+		result, err = ev.TryManyInt64()
+	case reflect.TypeFor[uint]():
+		result, err = ev.TryUint()
+	case reflect.TypeFor[[]uint]():
+		result, err = ev.TryManyUint()
+	case reflect.TypeFor[uint64]():
+		result, err = ev.TryUint64()
+	case reflect.TypeFor[[]uint64]():
+		result, err = ev.TryManyUint64()
+	case reflect.TypeFor[float64]():
+		result, err = ev.TryFloat64()
+	case reflect.TypeFor[[]float64]():
+		result, err = ev.TryManyFloat64()
+	case reflect.TypeFor[map[string]string]():
+		result, err = ev.TryStringMap()
+	default:
+		return ev.wrapErr(fmt.Errorf("unsupported field type %s", field.Type()))
+	}
+	if err != nil {
+		return err
+	}
+
+	field.Set(reflect.ValueOf(result))
+	return nil
+}
+
+// screamingSnakeCase converts a Go identifier to SCREAMING_SNAKE_CASE,
+// keeping acronyms together: DatabaseURL becomes DATABASE_URL and
+// HTTPServerPort becomes HTTP_SERVER_PORT.
+func screamingSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package genv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreamingSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Port":           "PORT",
+		"LogLevel":       "LOG_LEVEL",
+		"DatabaseURL":    "DATABASE_URL",
+		"HTTPServerPort": "HTTP_SERVER_PORT",
+		"APIKey":         "API_KEY",
+		"UserID":         "USER_ID",
+		"ID":             "ID",
+		"S3Bucket":       "S3_BUCKET",
+		"Port2":          "PORT2",
+		"camelCase":      "CAMEL_CASE",
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, screamingSnakeCase(name))
+		})
+	}
+}
+
+func TestParseInto(t *testing.T) {
+	type config struct {
+		DatabaseURL    string
+		HTTPServerPort int
+		Tagged         string `env:"CUSTOM_KEY"`
+		Debug          bool
+		Ratio          float64
+		MaxBytes       int64
+		Timeout        time.Duration
+		Hosts          []string
+		Labels         map[string]string
+		Optional       int    `env:",optional"`
+		Skipped        string `env:"-"`
+		unexported     string
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://db")
+		t.Setenv("HTTP_SERVER_PORT", "8080")
+		t.Setenv("CUSTOM_KEY", "tagged")
+		t.Setenv("TAGGED", "ignored")
+		t.Setenv("DEBUG", "true")
+		t.Setenv("RATIO", "0.5")
+		t.Setenv("MAX_BYTES", "8589934592")
+		t.Setenv("TIMEOUT", "5s")
+		t.Setenv("HOSTS", "a,b")
+		t.Setenv("LABELS", "team=core")
+		t.Setenv("SKIPPED", "ignored")
+
+		var cfg config
+		require.NoError(t, New().ParseInto(&cfg))
+		assert.Equal(t, config{
+			DatabaseURL:    "postgres://db",
+			HTTPServerPort: 8080,
+			Tagged:         "tagged",
+			Debug:          true,
+			Ratio:          0.5,
+			MaxBytes:       8589934592,
+			Timeout:        5 * time.Second,
+			Hosts:          []string{"a", "b"},
+			Labels:         map[string]string{"team": "core"},
+		}, cfg)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Setenv("HTTP_SERVER_PORT", "invalid")
+
+		var cfg config
+		err := New().ParseInto(&cfg)
+		assert.ErrorContains(t, err, "DATABASE_URL is invalid")
+		assert.ErrorContains(t, err, "HTTP_SERVER_PORT is invalid")
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		t.Setenv("CHANNEL", "x")
+		var cfg struct{ Channel chan int }
+		assert.ErrorContains(t, New().ParseInto(&cfg), "CHANNEL is invalid: unsupported field type chan int")
+	})
+
+	t.Run("NotStructPointer", func(t *testing.T) {
+		var cfg config
+		assert.Error(t, New().ParseInto(cfg))
+		assert.Error(t, New().ParseInto(new(int)))
+	})
+}
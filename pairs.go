@@ -0,0 +1,43 @@
+package genv
+
+import "fmt"
+
+// A key/value pair read from an element such as "auth=1".
+type Pair struct {
+	Key   string
+	Value string
+}
+
+// Key/value pairs in the order they were written, such as the steps of a
+// middleware chain. Keys are unique.
+type OrderedPairs []Pair
+
+// Returns the value for key and whether it was present.
+func (pairs OrderedPairs) Get(key string) (string, bool) {
+	for _, pair := range pairs {
+		if pair.Key == key {
+			return pair.Value, true
+		}
+	}
+	return "", false
+}
+
+// Returns the keys in order.
+func (pairs OrderedPairs) Keys() []string {
+	keys := make([]string, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+	return keys
+}
+
+func checkUniqueKeys(pairs []Pair) error {
+	seen := make(map[string]struct{}, len(pairs))
+	for _, pair := range pairs {
+		if _, ok := seen[pair.Key]; ok {
+			return fmt.Errorf("duplicate key %q", pair.Key)
+		}
+		seen[pair.Key] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package genv
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvarTryOrderedPairs(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "CHAIN", value: "log=2,auth=1,cache=", splitKey: ","}
+		pairs, err := ev.TryOrderedPairs()
+		require.NoError(t, err)
+		assert.Equal(t, OrderedPairs{{"log", "2"}, {"auth", "1"}, {"cache", ""}}, pairs)
+		assert.Equal(t, []string{"log", "auth", "cache"}, pairs.Keys())
+
+		value, ok := pairs.Get("auth")
+		assert.True(t, ok)
+		assert.Equal(t, "1", value)
+		_, ok = pairs.Get("missing")
+		assert.False(t, ok)
+	})
+
+	for name, test := range map[string]struct {
+		value string
+		err   string
+	}{
+		"notPair":      {"log=2,auth", `CHAIN is invalid: element 1: "auth" is not a key=value pair`},
+		"emptyKey":     {"=2", `CHAIN is invalid: element 0: "=2" is not a key=value pair`},
+		"duplicateKey": {"log=2,log=3", `CHAIN is invalid: duplicate key "log"`},
+		"empty":        {"", "CHAIN is invalid: environment variable is empty or unset"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "CHAIN", value: test.value, splitKey: ","}
+			_, err := ev.TryOrderedPairs()
+			assert.EqualError(t, err, test.err)
+		})
+	}
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "CHAIN", optional: true, splitKey: ","}
+		assert.Empty(t, ev.OrderedPairs())
+	})
+
+	t.Run("Panics", func(t *testing.T) {
+		ev := &Var{key: "CHAIN", value: "auth", splitKey: ","}
+		assert.Panics(t, func() { ev.OrderedPairs() })
+	})
+}
+
+func TestEvarTryStringMap(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "LABELS", value: "team=core,tier=", splitKey: ","}
+		labels, err := ev.TryStringMap()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "core", "tier": ""}, labels)
+	})
+
+	t.Run("PairSeparator", func(t *testing.T) {
+		genv := New()
+		ev := genv.Var("LABELS", func(v *Var) { v.value = "team:core;tier:1" })
+		labels := ev.StringMap(genv.WithSplitKey(";"), genv.WithPairSeparator(":"))
+		assert.Equal(t, map[string]string{"team": "core", "tier": "1"}, labels)
+	})
+
+	t.Run("MissingSeparator", func(t *testing.T) {
+		ev := &Var{key: "LABELS", value: "team=core,tier", splitKey: ","}
+		_, err := ev.TryStringMap()
+		assert.EqualError(t, err, `LABELS is invalid: element 1: "tier" is not a key=value pair`)
+		assert.Panics(t, func() { ev.StringMap() })
+	})
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "LABELS", optional: true, splitKey: ","}
+		labels, err := ev.TryStringMap()
+		require.NoError(t, err)
+		assert.Nil(t, labels)
+	})
+}
+
+func TestTryMap(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "WEIGHTS", value: "a=1,b=2", splitKey: ","}
+		weights, err := TryMap(ev, (*Var).TryInt)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, weights)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		ev := &Var{key: "WEIGHTS", value: "a=1,b=x", splitKey: ","}
+		_, err := TryMap(ev, (*Var).TryInt)
+		assert.EqualError(t, err, `WEIGHTS is invalid: key "b": strconv.Atoi: parsing "x": invalid syntax`)
+		assert.Panics(t, func() { Map(ev, (*Var).TryInt) })
+	})
+
+	t.Run("WrappedValue", func(t *testing.T) {
+		errUnknown := errors.New("unknown feature")
+		ev := &Var{key: "FLAGS", value: "a=on,b=x", splitKey: ","}
+		_, err := TryMap(ev, func(ev *Var) (bool, error) {
+			if ev.value != "on" {
+				return false, fmt.Errorf("feature %q: %w", ev.value, errUnknown)
+			}
+			return true, nil
+		})
+		assert.EqualError(t, err, `FLAGS is invalid: key "b": feature "x": unknown feature`)
+		assert.ErrorIs(t, err, errUnknown)
+	})
+
+	t.Run("DuplicateKey", func(t *testing.T) {
+		ev := &Var{key: "WEIGHTS", value: "a=1,a=2", splitKey: ","}
+		_, err := TryMap(ev, (*Var).TryInt)
+		assert.EqualError(t, err, `WEIGHTS is invalid: duplicate key "a"`)
+	})
+}
+
+func TestEvarTryMonthMap(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "RATES", value: "Jan=10,july=15,DEC=5", splitKey: ","}
+		rates, err := ev.TryMonthMap()
+		require.NoError(t, err)
+		assert.Equal(t, map[time.Month]int{time.January: 10, time.July: 15, time.December: 5}, rates)
+	})
+
+	for name, test := range map[string]struct {
+		value string
+		err   string
+	}{
+		"unknownMonth": {"Jan=10,Jux=15", `RATES is invalid: key "Jux": unknown month "Jux"`},
+		"badValue":     {"Jan=ten", `RATES is invalid: key "Jan": strconv.Atoi: parsing "ten": invalid syntax`},
+		"sameMonth":    {"Jan=1,January=2", "RATES is invalid: duplicate month January"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "RATES", value: test.value, splitKey: ","}
+			_, err := ev.TryMonthMap()
+			assert.EqualError(t, err, test.err)
+			assert.Panics(t, func() { ev.MonthMap() })
+		})
+	}
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "RATES", optional: true, splitKey: ","}
+		assert.Nil(t, ev.MonthMap())
+	})
+}
@@ -0,0 +1,97 @@
+package genv
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A choice and its relative weight, read from an element such as
+// "us-east-1a:2".
+type WeightedChoice struct {
+	Value  string
+	Weight int
+}
+
+// Picks among weighted choices at random, in proportion to their weights.
+type Sampler struct {
+	choices []WeightedChoice
+	// The running total of the weights up to and including each choice.
+	cumulative []int
+}
+
+// Fails if the total weight overflows an int, naming the choice at which it
+// does.
+func newSampler(choices []WeightedChoice) (Sampler, error) {
+	sampler := Sampler{choices: choices, cumulative: make([]int, len(choices))}
+	total := 0
+	for i, choice := range choices {
+		if choice.Weight > math.MaxInt-total {
+			return Sampler{}, fmt.Errorf("element %d: total weight overflows int", i)
+		}
+		total += choice.Weight
+		sampler.cumulative[i] = total
+	}
+	return sampler, nil
+}
+
+// Returns a choice picked using r. Returns "" if there are no choices.
+func (sampler Sampler) Pick(r *rand.Rand) string {
+	if len(sampler.choices) == 0 {
+		return ""
+	}
+	n := r.IntN(sampler.cumulative[len(sampler.cumulative)-1])
+	i := sort.SearchInts(sampler.cumulative, n+1)
+	return sampler.choices[i].Value
+}
+
+// Returns the choices in the order they were written.
+func (sampler Sampler) Choices() []WeightedChoice {
+	return sampler.choices
+}
+
+// Returns the value of the environment variable as weighted choices written
+// as value:weight pairs, such as "us-east-1a:2,us-east-1b:1". Panics if a
+// pair is malformed.
+func (ev *Var) Sampler(opts ...manyOpt) Sampler {
+	return mustParse(ev, func(ev *Var) (Sampler, error) {
+		return ev.TrySampler(opts...)
+	})
+}
+
+// Returns the value of the environment variable as weighted choices written
+// as value:weight pairs, such as "us-east-1a:2,us-east-1b:1". Weights must
+// be positive integers whose total fits in an int. The weight follows the last ":", so values may
+// contain colons themselves. An optional variable that is absent yields a
+// Sampler with no choices.
+func (ev *Var) TrySampler(opts ...manyOpt) (Sampler, error) {
+	choices, err := parseMany(ev, (*Var).tryWeightedChoice, opts...)
+	if err != nil {
+		return Sampler{}, err
+	}
+	sampler, err := newSampler(choices)
+	if err != nil {
+		return Sampler{}, ev.wrapErr(err)
+	}
+	return sampler, nil
+}
+
+func (ev *Var) tryWeightedChoice() (WeightedChoice, error) {
+	return parse(ev, func(value string) (WeightedChoice, error) {
+		i := strings.LastIndex(value, ":")
+		if i <= 0 {
+			return WeightedChoice{}, fmt.Errorf("%q is not a value:weight pair", value)
+		}
+		weight, err := strconv.Atoi(value[i+1:])
+		if err != nil {
+			return WeightedChoice{}, err
+		}
+		if weight <= 0 {
+			return WeightedChoice{}, fmt.Errorf("weight %d is not positive", weight)
+		}
+		return WeightedChoice{Value: value[:i], Weight: weight}, nil
+	})
+}
@@ -0,0 +1,62 @@
+package genv
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvarTrySampler(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "AZ", value: "us-east-1a:2,us-east-1b:1,host:8080:1", splitKey: ","}
+		sampler, err := ev.TrySampler()
+		require.NoError(t, err)
+		assert.Equal(t, []WeightedChoice{
+			{"us-east-1a", 2},
+			{"us-east-1b", 1},
+			{"host:8080", 1},
+		}, sampler.Choices())
+	})
+
+	for name, test := range map[string]struct {
+		value string
+		err   string
+	}{
+		"noWeight":   {"us-east-1a:2,us-east-1b", `AZ is invalid: element 1: "us-east-1b" is not a value:weight pair`},
+		"emptyValue": {":2", `AZ is invalid: element 0: ":2" is not a value:weight pair`},
+		"badWeight":  {"us-east-1a:x", `AZ is invalid: element 0: strconv.Atoi: parsing "x": invalid syntax`},
+		"zeroWeight": {"us-east-1a:2,us-east-1b:0", "AZ is invalid: element 1: weight 0 is not positive"},
+		"overflow":   {"a:9223372036854775807,b:1", "AZ is invalid: element 1: total weight overflows int"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := &Var{key: "AZ", value: test.value, splitKey: ","}
+			_, err := ev.TrySampler()
+			assert.EqualError(t, err, test.err)
+			assert.Panics(t, func() { ev.Sampler() })
+		})
+	}
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "AZ", optional: true, splitKey: ","}
+		sampler := ev.Sampler()
+		assert.Empty(t, sampler.Choices())
+		assert.Equal(t, "", sampler.Pick(rand.New(rand.NewPCG(1, 2))))
+	})
+}
+
+func TestSamplerPick(t *testing.T) {
+	ev := &Var{key: "AZ", value: "a:3,b:1", splitKey: ","}
+	sampler := ev.Sampler()
+	r := rand.New(rand.NewPCG(1, 2))
+
+	const samples = 100000
+	counts := map[string]int{}
+	for range samples {
+		counts[sampler.Pick(r)]++
+	}
+	assert.Len(t, counts, 2)
+	assert.InDelta(t, 0.75, float64(counts["a"])/samples, 0.01)
+	assert.InDelta(t, 0.25, float64(counts["b"])/samples, 0.01)
+}
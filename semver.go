@@ -0,0 +1,131 @@
+package genv
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A semantic version of the form MAJOR.MINOR.PATCH, optionally prefixed
+// with "v". Omitted minor and patch components are zero. Pre-release and
+// build metadata are not supported.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// Parses a version such as "1.2.3" or "v1.2".
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("%q is not a valid version", s)
+	}
+
+	var components [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || part[0] == '+' {
+			return Version{}, fmt.Errorf("%q is not a valid version", s)
+		}
+		components[i] = n
+	}
+	return Version{components[0], components[1], components[2]}, nil
+}
+
+// Returns -1, 0, or +1 depending on whether v is lower than, equal to,
+// or higher than other.
+func (v Version) Compare(other Version) int {
+	return cmp.Or(
+		cmp.Compare(v.Major, other.Major),
+		cmp.Compare(v.Minor, other.Minor),
+		cmp.Compare(v.Patch, other.Patch),
+	)
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// A set of comparisons that a version must satisfy all of, such as
+// ">=1.2.0 <2.0.0". Comparisons are separated by spaces or commas and use
+// one of the operators =, !=, <, <=, >, or >=, which may be set apart from
+// its version by spaces; a bare version means =.
+type Constraint struct {
+	terms []constraintTerm
+}
+
+type constraintTerm struct {
+	op      string
+	version Version
+}
+
+var constraintOps = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// Parses a constraint such as ">=1.2.0 <2.0.0".
+func ParseConstraint(s string) (Constraint, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	if len(fields) == 0 {
+		return Constraint{}, fmt.Errorf("%q is not a valid constraint", s)
+	}
+
+	terms := make([]constraintTerm, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		// An operator written apart from its version, as in ">= 1.2.0",
+		// applies to the field that follows it.
+		if slices.Contains(constraintOps, field) && i+1 < len(fields) {
+			i++
+			field += fields[i]
+		}
+		op := "="
+		for _, candidate := range constraintOps {
+			if rest, ok := strings.CutPrefix(field, candidate); ok {
+				op, field = candidate, rest
+				break
+			}
+		}
+		version, err := ParseVersion(field)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("%q is not a valid constraint: %w", s, err)
+		}
+		terms = append(terms, constraintTerm{op, version})
+	}
+	return Constraint{terms}, nil
+}
+
+// Reports whether version satisfies every comparison in the constraint.
+func (c Constraint) Check(version Version) bool {
+	for _, term := range c.terms {
+		result := version.Compare(term.version)
+		var ok bool
+		switch term.op {
+		case "=", "==":
+			ok = result == 0
+		case "!=":
+			ok = result != 0
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Constraint) String() string {
+	terms := make([]string, len(c.terms))
+	for i, term := range c.terms {
+		terms[i] = term.op + term.version.String()
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,101 @@
+package genv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	for name, test := range map[string]struct {
+		value    string
+		expected Version
+		err      bool
+	}{
+		"full":     {"1.2.3", Version{1, 2, 3}, false},
+		"prefixed": {"v1.2.3", Version{1, 2, 3}, false},
+		"partial":  {"1.2", Version{1, 2, 0}, false},
+		"tooLong":  {"1.2.3.4", Version{}, true},
+		"negative": {"1.-2.3", Version{}, true},
+		"signed":   {"+1.2.3", Version{}, true},
+		"empty":    {"", Version{}, true},
+		"letters":  {"1.x.3", Version{}, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ParseVersion(test.value)
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvarTrySemverConstraint(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := Var{key: "SUPPORTED", value: ">=1.2.0 <2.0.0"}
+		constraint, err := ev.TrySemverConstraint()
+		require.NoError(t, err)
+		assert.Equal(t, ">=1.2.0 <2.0.0", constraint.String())
+
+		for version, expected := range map[string]bool{
+			"1.1.9": false,
+			"1.2.0": true,
+			"1.9.9": true,
+			"2.0.0": false,
+		} {
+			v, err := ParseVersion(version)
+			require.NoError(t, err)
+			assert.Equal(t, expected, constraint.Check(v), version)
+		}
+	})
+
+	t.Run("Operators", func(t *testing.T) {
+		ev := Var{key: "SUPPORTED", value: ">1.0, <=1.5, !=1.3.0"}
+		constraint := ev.SemverConstraint()
+		assert.False(t, constraint.Check(Version{1, 0, 0}))
+		assert.True(t, constraint.Check(Version{1, 2, 0}))
+		assert.False(t, constraint.Check(Version{1, 3, 0}))
+		assert.True(t, constraint.Check(Version{1, 5, 0}))
+		assert.False(t, constraint.Check(Version{1, 5, 1}))
+	})
+
+	t.Run("SpacedOperators", func(t *testing.T) {
+		ev := Var{key: "SUPPORTED", value: ">= 1.2.0, < 2.0.0"}
+		constraint := ev.SemverConstraint()
+		assert.Equal(t, ">=1.2.0 <2.0.0", constraint.String())
+		assert.True(t, constraint.Check(Version{1, 2, 0}))
+		assert.False(t, constraint.Check(Version{2, 0, 0}))
+	})
+
+	t.Run("Exact", func(t *testing.T) {
+		ev := Var{key: "SUPPORTED", value: "1.4.2"}
+		constraint := ev.SemverConstraint()
+		assert.True(t, constraint.Check(Version{1, 4, 2}))
+		assert.False(t, constraint.Check(Version{1, 4, 3}))
+	})
+
+	for name, value := range map[string]string{
+		"badVersion":  ">=1.x",
+		"badOperator": "~>1.2.0",
+		"separators":  " , ",
+		"dangling":    ">=1.2.0 <",
+		"doubled":     ">= >= 1.2.0",
+	} {
+		t.Run(name, func(t *testing.T) {
+			ev := Var{key: "SUPPORTED", value: value}
+			_, err := ev.TrySemverConstraint()
+			assert.ErrorContains(t, err, "SUPPORTED is invalid")
+		})
+	}
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := Var{key: "SUPPORTED", optional: true}
+		constraint, err := ev.TrySemverConstraint()
+		assert.NoError(t, err)
+		assert.True(t, constraint.Check(Version{9, 9, 9}))
+	})
+}
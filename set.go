@@ -0,0 +1,92 @@
+package genv
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// A set of values with constant-time membership checks.
+type Set[T comparable] map[T]struct{}
+
+// Reports whether v is in the set.
+func (set Set[T]) Has(v T) bool {
+	_, ok := set[v]
+	return ok
+}
+
+// Returns the elements of the environment variable parsed with fn, such as
+// (*Var).TryInt, as a set. Repeated elements are kept once. Panics if the
+// variable is required but empty or an element fails to parse.
+func ManySet[T comparable](ev *Var, fn func(*Var) (T, error), opts ...manyOpt) Set[T] {
+	return mustParse(ev, func(ev *Var) (Set[T], error) {
+		return TryManySet(ev, fn, opts...)
+	})
+}
+
+// Returns the elements of the environment variable parsed with fn, such as
+// (*Var).TryInt, as a set. Repeated elements are kept once, and empty
+// elements are skipped as for any list. An optional variable that is
+// absent yields a nil set, which has no members.
+func TryManySet[T comparable](ev *Var, fn func(*Var) (T, error), opts ...manyOpt) (Set[T], error) {
+	elems, err := parseMany(ev, fn, opts...)
+	if err != nil || len(elems) == 0 {
+		return nil, err
+	}
+	set := make(Set[T], len(elems))
+	for _, elem := range elems {
+		set[elem] = struct{}{}
+	}
+	return set, nil
+}
+
+// A set of strings with constant-time membership checks, such as an
+// allow-list. It encodes to JSON as a sorted array.
+type StringSet map[string]struct{}
+
+// Reports whether s is in the set.
+func (set StringSet) Has(s string) bool {
+	_, ok := set[s]
+	return ok
+}
+
+func (set StringSet) MarshalJSON() ([]byte, error) {
+	elems := make([]string, 0, len(set))
+	for s := range set {
+		elems = append(elems, s)
+	}
+	slices.Sort(elems)
+	return json.Marshal(elems)
+}
+
+func (set *StringSet) UnmarshalJSON(data []byte) error {
+	var elems []string
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	*set = newStringSet(elems)
+	return nil
+}
+
+func newStringSet(elems []string) StringSet {
+	set := make(StringSet, len(elems))
+	for _, s := range elems {
+		set[s] = struct{}{}
+	}
+	return set
+}
+
+// Returns the elements of the environment variable as a set. Repeated
+// elements are kept once. Panics if the variable is required but empty.
+func (ev *Var) StringSet(opts ...manyOpt) StringSet {
+	return mustParse(ev, func(ev *Var) (StringSet, error) {
+		return ev.TryStringSet(opts...)
+	})
+}
+
+// Returns the elements of the environment variable as a set. Repeated
+// elements are kept once. An optional variable that is absent yields a nil
+// set, which has no members.
+func (ev *Var) TryStringSet(opts ...manyOpt) (StringSet, error) {
+	set, err := TryManySet(ev, (*Var).TryString, opts...)
+	return StringSet(set), err
+}
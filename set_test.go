@@ -0,0 +1,72 @@
+package genv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvarTryStringSet(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "ALLOWED_HOSTS", value: "b.local,a.local,,b.local", splitKey: ","}
+		set, err := ev.TryStringSet()
+		require.NoError(t, err)
+		assert.Equal(t, StringSet{"a.local": {}, "b.local": {}}, set)
+		assert.True(t, set.Has("a.local"))
+		assert.False(t, set.Has("c.local"))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		ev := &Var{key: "ALLOWED_HOSTS", splitKey: ","}
+		_, err := ev.TryStringSet()
+		assert.ErrorIs(t, err, ErrRequiredEnvironmentVariable)
+		assert.Panics(t, func() { ev.StringSet() })
+	})
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "ALLOWED_HOSTS", optional: true, splitKey: ","}
+		set := ev.StringSet()
+		assert.Nil(t, set)
+		assert.False(t, set.Has("a.local"))
+	})
+}
+
+func TestStringSetJSON(t *testing.T) {
+	set := StringSet{"b": {}, "a": {}, "c": {}}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	assert.Equal(t, `["a","b","c"]`, string(data))
+
+	var decoded StringSet
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, set, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"a":1}`), &decoded))
+}
+
+func TestTryManySet(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		ev := &Var{key: "PORTS", value: "80,443,,80,8080", splitKey: ","}
+		set, err := TryManySet(ev, (*Var).TryInt)
+		require.NoError(t, err)
+		assert.Equal(t, Set[int]{80: {}, 443: {}, 8080: {}}, set)
+		assert.True(t, set.Has(443))
+		assert.False(t, set.Has(22))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		ev := &Var{key: "PORTS", value: "80,http", splitKey: ","}
+		_, err := TryManySet(ev, (*Var).TryInt)
+		assert.ErrorContains(t, err, "PORTS is invalid: element 1: ")
+		assert.Panics(t, func() { ManySet(ev, (*Var).TryInt) })
+	})
+
+	t.Run("Optional", func(t *testing.T) {
+		ev := &Var{key: "PORTS", optional: true, splitKey: ","}
+		set := ManySet(ev, (*Var).TryInt)
+		assert.Nil(t, set)
+		assert.False(t, set.Has(80))
+	})
+}
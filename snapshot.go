@@ -0,0 +1,81 @@
+package genv
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// An immutable copy of the variables declared on an instance, as returned
+// by Genv.Snapshot. Its getters parse the resolved values with the same
+// rules as the variables themselves, so a snapshot can be passed around in
+// place of the instance.
+type Config struct {
+	vars map[string]Var
+	keys []string
+}
+
+// Returns a snapshot of the variables declared so far, including those
+// declared on groups, keyed by their full keys. Later changes to the
+// environment or to the instance do not affect the snapshot.
+func (genv *Genv) Snapshot() Config {
+	vars := genv.registry.all()
+	config := Config{vars: make(map[string]Var, len(vars)), keys: make([]string, len(vars))}
+	for i, ev := range vars {
+		config.vars[ev.key] = *ev
+		config.keys[i] = ev.key
+	}
+	return config
+}
+
+// Returns the keys in the snapshot in declaration order.
+func (config Config) Keys() []string {
+	return append([]string(nil), config.keys...)
+}
+
+// Reports whether key is in the snapshot.
+func (config Config) Has(key string) bool {
+	_, ok := config.vars[key]
+	return ok
+}
+
+func (config Config) String(key string) (string, error) {
+	return getConfig(config, key, (*Var).TryString)
+}
+
+func (config Config) Bool(key string) (bool, error) {
+	return getConfig(config, key, (*Var).TryBool)
+}
+
+func (config Config) Int(key string) (int, error) {
+	return getConfig(config, key, (*Var).TryInt)
+}
+
+func (config Config) Float64(key string) (float64, error) {
+	return getConfig(config, key, (*Var).TryFloat64)
+}
+
+func (config Config) Duration(key string) (time.Duration, error) {
+	return getConfig(config, key, (*Var).TryDuration)
+}
+
+func (config Config) URL(key string) (*url.URL, error) {
+	return getConfig(config, key, (*Var).TryURL)
+}
+
+func (config Config) ManyString(key string) ([]string, error) {
+	return getConfig(config, key, func(ev *Var) ([]string, error) {
+		return ev.TryManyString()
+	})
+}
+
+// Parses the value of key with fn, working on a copy of its variable so
+// that the snapshot itself is never modified.
+func getConfig[T any](config Config, key string, fn func(*Var) (T, error)) (T, error) {
+	ev, ok := config.vars[key]
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%s is not in the snapshot", key)
+	}
+	return fn(&ev)
+}
@@ -0,0 +1,67 @@
+package genv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	t.Setenv("PORT", "8080")
+	t.Setenv("DEBUG", "true")
+	t.Setenv("TIMEOUT", "5s")
+	t.Setenv("HOSTS", "a,b")
+	t.Setenv("DB_URL", "postgres://db")
+	genv := New()
+	genv.Var("HOST")
+	genv.Var("PORT")
+	genv.Var("DEBUG")
+	genv.Var("TIMEOUT")
+	genv.Var("HOSTS")
+	genv.Var("MISSING").Optional()
+	genv.Group("DB").Var("URL")
+
+	config := genv.Snapshot()
+	t.Setenv("HOST", "changed")
+	genv.Var("LATER")
+
+	assert.Equal(t, []string{"HOST", "PORT", "DEBUG", "TIMEOUT", "HOSTS", "MISSING", "DB_URL"}, config.Keys())
+	assert.False(t, config.Has("LATER"))
+
+	host, err := config.String("HOST")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", host)
+
+	port, err := config.Int("PORT")
+	require.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	debug, err := config.Bool("DEBUG")
+	require.NoError(t, err)
+	assert.True(t, debug)
+
+	timeout, err := config.Duration("TIMEOUT")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, timeout)
+
+	hosts, err := config.ManyString("HOSTS")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, hosts)
+
+	dbURL, err := config.URL("DB_URL")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://db", dbURL.String())
+
+	missing, err := config.Float64("MISSING")
+	require.NoError(t, err)
+	assert.Zero(t, missing)
+
+	_, err = config.Int("HOST")
+	assert.EqualError(t, err, `HOST is invalid: strconv.Atoi: parsing "localhost": invalid syntax`)
+
+	_, err = config.String("UNKNOWN")
+	assert.EqualError(t, err, "UNKNOWN is not in the snapshot")
+}
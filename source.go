@@ -0,0 +1,85 @@
+package genv
+
+import "os"
+
+// Provides the values of variables in place of the process environment,
+// such as a secret store, or a fixed set of values in tests.
+type Source interface {
+	// Returns the value of key and whether it was present.
+	Lookup(key string) (string, bool)
+}
+
+// A Source backed by a map of keys to values.
+type MapSource map[string]string
+
+func (source MapSource) Lookup(key string) (string, bool) {
+	value, found := source[key]
+	return value, found
+}
+
+// The default Source, backed by the process environment. Use it with
+// WithSources to layer the environment among other sources.
+type EnvSource struct{}
+
+func (EnvSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// A copy of the process environment, taken by WithSnapshot, whose values
+// are reported as coming from the environment.
+type envSnapshot map[string]string
+
+func (snapshot envSnapshot) Lookup(key string) (string, bool) {
+	value, found := snapshot[key]
+	return value, found
+}
+
+// Reads variables from source instead of the process environment. Values
+// loaded from files, defaults, and the other options that apply to the
+// environment apply to source in the same way.
+func WithSource(source Source) genvOpt {
+	return func(genv *Genv) {
+		genv.source = source
+	}
+}
+
+// Reads variables from each of sources in turn, using the value from the
+// first source where a variable is present. Sources given earlier therefore
+// take precedence, so WithSources(vault, EnvSource{}) lets a secret store
+// override the environment. Only when every source misses are values
+// loaded from files consulted, and then any Default.
+func WithSources(sources ...Source) genvOpt {
+	return WithSource(chainSource(sources))
+}
+
+type chainSource []Source
+
+func (sources chainSource) Lookup(key string) (string, bool) {
+	for _, source := range sources {
+		if value, found := source.Lookup(key); found {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Looks up key in source, reporting whether the value came from the process
+// environment or another source. For a chain, the origin is that of the
+// source that answered.
+func lookupOrigin(source Source, key string) (string, Origin, bool) {
+	switch source := source.(type) {
+	case EnvSource, envSnapshot:
+		value, found := source.Lookup(key)
+		return value, OriginEnv, found
+	case chainSource:
+		for _, source := range source {
+			if value, origin, found := lookupOrigin(source, key); found {
+				return value, origin, true
+			}
+		}
+		return "", OriginUnset, false
+	default:
+		value, found := source.Lookup(key)
+		return value, OriginSource, found
+	}
+}
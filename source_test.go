@@ -0,0 +1,99 @@
+package genv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSource(t *testing.T) {
+	t.Setenv("REAL_VAR", "real")
+	genv := New(
+		WithSource(MapSource{"HOST": "vault.local", "PORT": "8200", "EMPTY": "", "DB_HOST": "db.local"}),
+		WithAllowDefault(func(*Genv) bool { return true }),
+	)
+
+	assert.Equal(t, "vault.local", genv.Var("HOST").String())
+	assert.Equal(t, 8200, genv.Var("PORT").Int())
+	assert.True(t, genv.Var("EMPTY").found)
+	assert.False(t, genv.Var("REAL_VAR").found)
+	assert.Equal(t, "fallback", genv.Var("MISSING").Default("fallback").String())
+	assert.Equal(t, "db.local", genv.Group("DB").Var("HOST").String())
+}
+
+func TestDefaultSource(t *testing.T) {
+	t.Setenv("REAL_VAR", "real")
+	assert.Equal(t, "real", New().Var("REAL_VAR").String())
+}
+
+// Counts lookups so that tests can check which sources were consulted.
+type countingSource struct {
+	MapSource
+	lookups int
+}
+
+func (source *countingSource) Lookup(key string) (string, bool) {
+	source.lookups++
+	return source.MapSource.Lookup(key)
+}
+
+func TestWithSources(t *testing.T) {
+	t.Setenv("SHARED", "env")
+	t.Setenv("ENV_ONLY", "env")
+	remote := &countingSource{MapSource: MapSource{"SHARED": "remote", "REMOTE_ONLY": "remote"}}
+	fallback := &countingSource{MapSource: MapSource{"SHARED": "fallback", "FALLBACK_ONLY": "fallback"}}
+	genv := New(
+		WithSources(remote, EnvSource{}, fallback),
+		WithAllowDefault(func(*Genv) bool { return true }),
+	)
+
+	t.Run("FirstHit", func(t *testing.T) {
+		remote.lookups, fallback.lookups = 0, 0
+		assert.Equal(t, "remote", genv.Var("SHARED").String())
+		assert.Equal(t, 1, remote.lookups)
+		assert.Zero(t, fallback.lookups)
+	})
+
+	t.Run("FallsThrough", func(t *testing.T) {
+		assert.Equal(t, "env", genv.Var("ENV_ONLY").String())
+		assert.Equal(t, "fallback", genv.Var("FALLBACK_ONLY").String())
+	})
+
+	t.Run("AllMiss", func(t *testing.T) {
+		assert.Equal(t, "default", genv.Var("MISSING").Default("default").String())
+	})
+}
+
+func TestProvenance(t *testing.T) {
+	path := writeDotEnv(t, "FILE_VAR=file\n")
+	t.Setenv("ENV_VAR", "env")
+	t.Setenv("OVERRIDE_VAR", "env")
+	genv := New(WithDotEnv(path), WithAllowDefault(func(*Genv) bool { return true }))
+
+	genv.Var("ENV_VAR")
+	genv.Var("FILE_VAR")
+	genv.Var("DEFAULT_VAR").Default("default")
+	genv.Var("UNSET_VAR").Optional()
+	genv.Var("OVERRIDE_VAR").Default("ignored")
+
+	assert.Equal(t, map[string]Origin{
+		"ENV_VAR":      OriginEnv,
+		"FILE_VAR":     OriginFile,
+		"DEFAULT_VAR":  OriginDefault,
+		"UNSET_VAR":    OriginUnset,
+		"OVERRIDE_VAR": OriginEnv,
+	}, genv.Provenance())
+
+	t.Run("Source", func(t *testing.T) {
+		genv := New(WithSources(MapSource{"REMOTE_VAR": "remote"}, EnvSource{}))
+		assert.Equal(t, OriginSource, genv.Var("REMOTE_VAR").Origin())
+		assert.Equal(t, OriginEnv, genv.Var("ENV_VAR").Origin())
+		assert.Equal(t, OriginUnset, genv.Var("MISSING_VAR").Origin())
+	})
+
+	t.Run("OnlyIf", func(t *testing.T) {
+		t.Setenv("CACHE_BACKEND", "memory")
+		ev := New().Var("ENV_VAR").OnlyIf("CACHE_BACKEND", "redis")
+		assert.Equal(t, OriginUnset, ev.Origin())
+	})
+}